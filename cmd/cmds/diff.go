@@ -0,0 +1,163 @@
+package cmds
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/springernature/halfpipe"
+	"github.com/springernature/halfpipe/diff"
+	"github.com/springernature/halfpipe/project"
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [git ref]",
+	Short: "Shows what the changes to the halfpipe manifest do to the rendered pipeline",
+	Long: `Renders the halfpipe manifest at the git ref, HEAD by default, and in the working tree
+and prints the jobs, resources and steps that are added, removed or changed`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := "HEAD"
+		if len(args) == 1 {
+			ref = args[0]
+		}
+
+		fs := afero.Afero{Fs: afero.NewOsFs()}
+
+		currentDir, err := os.Getwd()
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+		currentDir, halfpipeFilenameOptions := resolveInput(currentDir, formatInput(Input))
+
+		projectData, err := project.NewProjectResolver(fs).Parse(currentDir, false, halfpipeFilenameOptions)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+
+		manifests, err := getManifests(fs, currentDir, projectData.HalfpipeFilePath)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+
+		var newPipelines []halfpipe.Response
+		for _, m := range manifests {
+			if len(m.errors) > 0 {
+				outputLintResults(manifestLintResults(m.errors), projectData)
+			}
+			response, err := createController(projectData, fs, currentDir, createRenderer(projectData, m.man), m.sourceMap).Process(m.man)
+			if err != nil {
+				printErr(err)
+				os.Exit(1)
+			}
+			outputLintResults(response.LintResults, projectData)
+			newPipelines = append(newPipelines, response)
+		}
+
+		oldPipelines, err := renderAtRef(ref, projectData)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+
+		printPipelineDiffs(ref, oldPipelines, newPipelines, manifests)
+	},
+}
+
+// renderAtRef renders the pipelines of the halfpipe manifest as it is in the git ref, with the includes and vars files as they are in the ref.
+// The manifest is not linted as the linters check the files in the working tree, not in the ref.
+func renderAtRef(ref string, projectData project.Data) (pipelines map[string]halfpipe.Response, err error) {
+	pipelines = map[string]halfpipe.Response{}
+
+	manifestYaml, found, err := gitShow(ref, projectData.HalfpipeFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		// the manifest is new, so all pipelines are added
+		return pipelines, nil
+	}
+
+	readFile := func(filePath string) (string, error) {
+		content, found, err := gitShow(ref, filePath)
+		if err == nil && !found {
+			err = fmt.Errorf("file not found '%s' in '%s'", filePath, ref)
+		}
+		return content, err
+	}
+
+	pipelines, err = renderWithoutLinting(projectData, manifestYaml, readFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not render %s at '%s' : %w", projectData.HalfpipeFilePath, ref, err)
+	}
+	return pipelines, nil
+}
+
+// gitShow returns the content of the file, relative to the working directory, in the git ref
+func gitShow(ref string, filePath string) (content string, found bool, err error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "show", fmt.Sprintf("%s:./%s", ref, filePath)) // nolint: gosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stderr.String(), "exists on disk, but not in") || strings.Contains(stderr.String(), "does not exist in") {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("could not read %s at '%s' : %s", filePath, ref, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), true, nil
+}
+
+// printPipelineDiffs prints the diff of every pipeline, pipelines are matched on their name
+func printPipelineDiffs(ref string, oldPipelines map[string]halfpipe.Response, newPipelines []halfpipe.Response, manifests []parsedManifest) {
+	seen := map[string]bool{}
+	for i, response := range newPipelines {
+		name := manifests[i].man.PipelineName()
+		seen[name] = true
+
+		old, found := oldPipelines[name]
+		if !found {
+			fmt.Printf("Pipeline '%s' is added\n\n", name)
+			continue
+		}
+
+		fmt.Printf("Pipeline '%s' (%s -> working tree)\n", name, ref)
+		if old.Platform != response.Platform {
+			fmt.Printf("Platform changed from %s to %s\n", old.Platform, response.Platform)
+		}
+
+		diffPipelines := diff.Concourse
+		if response.Platform.IsActions() {
+			diffPipelines = diff.Actions
+		}
+		d, err := diffPipelines(old.ConfigYaml, response.ConfigYaml)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+		fmt.Println(d)
+	}
+
+	var removed []string
+	for name := range oldPipelines {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		if !seen[name] {
+			fmt.Printf("Pipeline '%s' is removed\n\n", name)
+		}
+	}
+}
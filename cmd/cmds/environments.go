@@ -0,0 +1,71 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/springernature/halfpipe/renderers/actions"
+)
+
+func init() {
+	rootCmd.AddCommand(environmentsCmd)
+	environmentsCmd.Flags().BoolVar(&All, "all", false, "Lists the environments of every halfpipe manifest in the git repo")
+}
+
+var environmentsCmd = &cobra.Command{
+	Use:   "environments",
+	Short: "Lists the GitHub environments the workflows need for tasks with manual_trigger",
+	Long: `Lists the GitHub environments the workflows need for tasks with manual_trigger.
+A task with manual_trigger is rendered as a job in the environment with the name of the task,
+add required reviewers to the environment so that the job waits for their approval`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+		defer os.Chdir(currentDir) // nolint: errcheck
+
+		found := false
+		for _, p := range getPipelines(formatInput(Input), All) {
+			if len(p.manifestErrors) > 0 {
+				outputLintResults(manifestLintResults(p.manifestErrors), p.projectData)
+				continue
+			}
+			if !p.man.Platform.IsActions() {
+				continue
+			}
+
+			// the defaulter resolves files relative to the working directory
+			if err := os.Chdir(p.dir); err != nil {
+				printErr(err)
+				os.Exit(1)
+			}
+			man, err := p.controller.DefaultAndMap(p.man)
+			if err != nil {
+				printErr(err)
+				os.Exit(1)
+			}
+
+			environments := actions.Environments(man)
+			if len(environments) == 0 {
+				continue
+			}
+
+			if !found {
+				fmt.Printf("Create the environments with required reviewers in %s\n", actions.NewActions(p.projectData.GitURI, p.projectData.HalfpipeFilePath).EnvironmentsURL())
+				found = true
+			}
+			fmt.Printf("\n%s\n", man.PipelineName())
+			for _, environment := range environments {
+				fmt.Printf("  %s\n", environment)
+			}
+		}
+
+		if !found {
+			fmt.Println("No environments needed, there are no tasks with manual_trigger in GitHub Actions workflows")
+		}
+	},
+}
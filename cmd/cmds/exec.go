@@ -26,7 +26,7 @@ var execCmd = &cobra.Command{
 			printErr(err)
 			os.Exit(1)
 		}
-		outputLintResults(response.LintResults)
+		outputLintResults(response.LintResults, response.Project)
 		fmt.Println(response)
 	},
 }
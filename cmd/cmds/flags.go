@@ -4,6 +4,10 @@ var Quiet bool
 
 var Input string
 
+var OutputFormat string
+
+var All bool
+
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&Input, "input", "i", "", "Sets the halfpipe filename to be used")
 
@@ -3,6 +3,7 @@ package cmds
 import (
 	"fmt"
 	"github.com/springernature/halfpipe/renderers/concourse"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -35,38 +36,104 @@ var checkVersion = func() (err error) {
 
 func formatInput(input string) []string {
 	halfpipeFilenameOptions := config.HalfpipeFilenameOptions
-	if Input == "" {
+	if input == "" {
 		return halfpipeFilenameOptions
 	}
-	if strings.Contains(input, string(os.PathSeparator)) {
-		fmt.Printf("Input file '%s' must be in current directory\n", input)
+	return []string{input}
+
+}
+
+// resolveInput returns the directory of the halfpipe manifest and the filenames to look for in it.
+// An input in another directory, e.g. 'services/api/.halfpipe.io', changes to that directory
+// so halfpipe behaves as if it was executed there.
+func resolveInput(currentDir string, halfpipeFilenameOptions []string) (workingDir string, options []string) {
+	if len(halfpipeFilenameOptions) != 1 || !strings.ContainsAny(halfpipeFilenameOptions[0], `/\`) {
+		return currentDir, halfpipeFilenameOptions
+	}
+
+	input := filepath.FromSlash(halfpipeFilenameOptions[0])
+	workingDir = filepath.Join(currentDir, filepath.Dir(input))
+	if err := os.Chdir(workingDir); err != nil {
+		printErr(fmt.Errorf("input file '%s' : %w", halfpipeFilenameOptions[0], err))
 		os.Exit(1)
 	}
-	return []string{input}
+	return workingDir, []string{filepath.Base(input)}
+}
 
+type parsedManifest struct {
+	man       manifest.Manifest
+	sourceMap manifest.SourceMap
+	errors    []error
 }
 
-func getManifest(fs afero.Afero, currentDir, halfpipeFilePath string) (man manifest.Manifest, errors []error) {
+// getManifests parses all the pipelines in the halfpipe manifest, one per yaml document
+func getManifests(fs afero.Afero, currentDir, halfpipeFilePath string) (manifests []parsedManifest, err error) {
 	yaml, err := linters.ReadFile(fs, path.Join(currentDir, halfpipeFilePath))
+	if err != nil {
+		return nil, err
+	}
+
+	readFile := func(filePath string) (string, error) {
+		return linters.ReadFile(fs, path.Join(currentDir, filePath))
+	}
+
+	for _, document := range manifest.SplitDocuments(yaml) {
+		man, sourceMap, errs := manifest.ParseWithIncludes(halfpipeFilePath, document, readFile)
+		manifests = append(manifests, parsedManifest{man: man, sourceMap: sourceMap, errors: errs})
+	}
+	return manifests, nil
+}
+
+func getManifest(fs afero.Afero, currentDir, halfpipeFilePath string) (man manifest.Manifest, sourceMap manifest.SourceMap, errors []error) {
+	manifests, err := getManifests(fs, currentDir, halfpipeFilePath)
 	if err != nil {
 		errors = append(errors, err)
 		return
 	}
 
-	man, errs := manifest.Parse(yaml)
-	if len(errs) != 0 {
-		errors = append(errors, errs...)
+	if len(manifests) > 1 {
+		errors = append(errors, fmt.Errorf("%s contains %d pipelines, this command only supports a manifest with a single pipeline", halfpipeFilePath, len(manifests)))
 		return
 	}
 
-	return
+	return manifests[0].man, manifests[0].sourceMap, manifests[0].errors
+}
+
+// renderWithoutLinting renders all pipelines in the manifest by their name, it is used to render a manifest that is not in the working tree
+func renderWithoutLinting(projectData project.Data, manifestYaml string, readFile manifest.ReadFile) (map[string]halfpipe.Response, error) {
+	pipelines := map[string]halfpipe.Response{}
+	for _, document := range manifest.SplitDocuments(manifestYaml) {
+		man, sourceMap, errs := manifest.ParseWithIncludes(projectData.HalfpipeFilePath, document, readFile)
+		if len(errs) > 0 {
+			return nil, errs[0]
+		}
+
+		renderer := createRenderer(projectData, man)
+		response, err := halfpipe.NewController(createDefaulter(projectData, renderer), mapper.New(), nil, renderer, sourceMap).Process(man)
+		if err != nil {
+			return nil, err
+		}
+		if response.LintResults.HasErrors() {
+			return nil, fmt.Errorf("\n%s", response.LintResults)
+		}
+		pipelines[man.PipelineName()] = response
+	}
+	return pipelines, nil
 }
 
 func printErr(err error) {
 	fmt.Fprintln(os.Stderr, err) // nolint: gas
 }
 
-func outputLintResults(lintResults linters.LintResults) {
+// lintResultsWriter is where the lint results are written to when --output-format is given
+var lintResultsWriter io.Writer = os.Stderr
+
+func outputLintResults(lintResults linters.LintResults, projectData project.Data) {
+	if OutputFormat != "" {
+		outputLintReport(lintResults, projectData)
+		return
+	}
+
 	if lintResults.HasWarnings() && !lintResults.HasErrors() && !Quiet {
 		printErr(lintResults)
 		return
@@ -78,9 +145,36 @@ func outputLintResults(lintResults linters.LintResults) {
 	}
 }
 
+func outputLintReport(lintResults linters.LintResults, projectData project.Data) {
+	var report []byte
+	var err error
+	switch OutputFormat {
+	case "json":
+		report, err = lintResults.JSON(projectData.BasePath)
+	case "sarif":
+		report, err = lintResults.SARIF(projectData.BasePath, path.Join(projectData.BasePath, projectData.HalfpipeFilePath))
+	default:
+		err = fmt.Errorf("unknown output format '%s', must be one of 'json' or 'sarif'", OutputFormat)
+	}
+
+	if err != nil {
+		printErr(err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(lintResultsWriter, string(report)) // nolint: gas
+
+	if lintResults.HasErrors() {
+		os.Exit(1)
+	}
+}
+
 func renderResponse(r halfpipe.Response, filePath string) {
-	outputLintResults(r.LintResults)
+	outputLintResults(r.LintResults, r.Project)
+	writePipeline(r, filePath)
+}
 
+func writePipeline(r halfpipe.Response, filePath string) {
 	outputYaml := fmt.Sprintf("# Generated using halfpipe cli version %s from file %s\n%s", config.Version, filepath.Join(r.Project.BasePath, r.Project.HalfpipeFilePath), r.ConfigYaml)
 
 	if filePath == "" {
@@ -115,7 +209,7 @@ func createDefaulter(projectData project.Data, renderer halfpipe.Renderer) defau
 	}
 }
 
-func createController(projectData project.Data, fs afero.Afero, currentDir string, renderer halfpipe.Renderer) halfpipe.Controller {
+func createController(projectData project.Data, fs afero.Afero, currentDir string, renderer halfpipe.Renderer, sourceMap manifest.SourceMap) halfpipe.Controller {
 	return halfpipe.NewController(
 		createDefaulter(projectData, renderer),
 		mapper.New(),
@@ -123,11 +217,12 @@ func createController(projectData project.Data, fs afero.Afero, currentDir strin
 			linters.NewTopLevelLinter(),
 			linters.NewTriggersLinter(fs, currentDir, project.BranchResolver, gitconfig.OriginURL),
 			linters.NewSecretsLinter(manifest.NewSecretValidator()),
-			linters.NewTasksLinter(fs, runtime.GOOS),
+			linters.NewTasksLinter(fs, currentDir, runtime.GOOS),
 			linters.NewFeatureToggleLinter(manifest.AvailableFeatureToggles),
 			linters.NewActionsLinter(gitconfig.OriginURL),
 		},
 		renderer,
+		sourceMap,
 	)
 
 }
@@ -145,6 +240,7 @@ func getManifestAndController(halfpipeFilenameOptions []string, renderer halfpip
 		printErr(err)
 		os.Exit(1)
 	}
+	currentDir, halfpipeFilenameOptions = resolveInput(currentDir, halfpipeFilenameOptions)
 
 	projectData, err := project.NewProjectResolver(fs).Parse(currentDir, false, halfpipeFilenameOptions)
 	if err != nil {
@@ -152,19 +248,26 @@ func getManifestAndController(halfpipeFilenameOptions []string, renderer halfpip
 		os.Exit(1)
 	}
 
-	man, manErrors := getManifest(fs, currentDir, projectData.HalfpipeFilePath)
+	man, sourceMap, manErrors := getManifest(fs, currentDir, projectData.HalfpipeFilePath)
 	if len(manErrors) > 0 {
-		outputLintResults(linters.LintResults{linters.NewLintResult("Halfpipe Manifest", "https://ee.public.springernature.app/rel-eng/halfpipe/manifest/", manErrors)})
+		outputLintResults(manifestLintResults(manErrors), projectData)
 	}
 
 	if renderer == nil {
-		if man.Platform.IsActions() {
-			renderer = actions.NewActions(projectData.GitURI, projectData.HalfpipeFilePath)
-		} else {
-			renderer = concourse.NewPipeline(projectData.HalfpipeFilePath)
-		}
+		renderer = createRenderer(projectData, man)
 	}
-	controller := createController(projectData, fs, currentDir, renderer)
+	controller := createController(projectData, fs, currentDir, renderer, sourceMap)
 
 	return man, controller
 }
+
+func createRenderer(projectData project.Data, man manifest.Manifest) halfpipe.Renderer {
+	if man.Platform.IsActions() {
+		return actions.NewActions(projectData.GitURI, projectData.HalfpipeFilePath)
+	}
+	return concourse.NewPipeline(projectData.HalfpipeFilePath)
+}
+
+func manifestLintResults(errs []error) linters.LintResults {
+	return linters.LintResults{linters.NewLintResult("Halfpipe Manifest", "https://ee.public.springernature.app/rel-eng/halfpipe/manifest/", errs)}
+}
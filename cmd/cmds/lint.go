@@ -0,0 +1,37 @@
+package cmds
+
+import (
+	"github.com/spf13/cobra"
+	"os"
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolVar(&All, "all", false, "Lints every halfpipe manifest in the git repo")
+	lintCmd.Flags().StringVar(&OutputFormat, "output-format", "", "Prints the lint results in this format, one of 'json' or 'sarif'")
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Lints the halfpipe manifest without rendering the pipeline",
+	Long: `Lints the halfpipe manifest without rendering the pipeline.
+Use --output-format json or --output-format sarif to get results that can be consumed by other tools, e.g. GitHub code scanning.
+Use --all to lint every halfpipe manifest in the git repo in one report`,
+	Run: func(cmd *cobra.Command, args []string) {
+		lintResultsWriter = os.Stdout
+
+		pipelines := getPipelines(formatInput(Input), All)
+		if All || len(pipelines) > 1 {
+			processPipelines(pipelines, false)
+			return
+		}
+
+		man, controller := pipelines[0].man, pipelines[0].controller
+		if len(pipelines[0].manifestErrors) > 0 {
+			outputLintResults(manifestLintResults(pipelines[0].manifestErrors), pipelines[0].projectData)
+		}
+
+		response := controller.Lint(man)
+		outputLintResults(response.LintResults, response.Project)
+	},
+}
@@ -0,0 +1,171 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/springernature/halfpipe/linters"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/migrate"
+	"github.com/springernature/halfpipe/project"
+)
+
+var dryRun bool
+
+func init() {
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Prints the changes as a diff instead of writing them")
+	rootCmd.AddCommand(migrateCmd)
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrites deprecated fields in the halfpipe manifest to the current structure",
+	Long: `Rewrites deprecated fields in the halfpipe manifest, and 'buildpack' in the Cloud Foundry manifests
+of deploy-cf tasks, to the current structure. Comments and the order of the fields are kept.
+The manifest is only written if the rendered pipeline stays the same`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fs := afero.Afero{Fs: afero.NewOsFs()}
+
+		currentDir, err := os.Getwd()
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+		currentDir, halfpipeFilenameOptions := resolveInput(currentDir, formatInput(Input))
+
+		projectData, err := project.NewProjectResolver(fs).Parse(currentDir, false, halfpipeFilenameOptions)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+
+		migrated, err := migrateFiles(fs, currentDir, projectData)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+
+		if len(migrated) == 0 {
+			fmt.Println("Nothing to migrate")
+			return
+		}
+
+		for _, file := range migrated {
+			if dryRun {
+				fmt.Print(file.diff())
+				continue
+			}
+
+			if err := fs.WriteFile(path.Join(currentDir, file.path), []byte(file.migrated), 0644); err != nil {
+				printErr(err)
+				os.Exit(1)
+			}
+			fmt.Printf("Migrated %s\n", file.path)
+			for _, change := range file.changes {
+				fmt.Printf("  %s\n", change)
+			}
+		}
+	},
+}
+
+type migratedFile struct {
+	path     string
+	original string
+	migrated string
+	changes  []string
+}
+
+func (f migratedFile) diff() string {
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(f.original),
+		B:        difflib.SplitLines(f.migrated),
+		FromFile: "a/" + f.path,
+		ToFile:   "b/" + f.path,
+		Context:  3,
+	})
+	return diff
+}
+
+// migrateFiles migrates the halfpipe manifest and the Cloud Foundry manifests of its deploy-cf tasks,
+// it only returns the files that have changed
+func migrateFiles(fs afero.Afero, currentDir string, projectData project.Data) (files []migratedFile, err error) {
+	readFile := func(filePath string) (string, error) {
+		return linters.ReadFile(fs, path.Join(currentDir, filePath))
+	}
+
+	original, err := readFile(projectData.HalfpipeFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	migrated, changes, err := migrate.Manifest(original)
+	if err != nil {
+		return nil, fmt.Errorf("could not migrate %s : %w", projectData.HalfpipeFilePath, err)
+	}
+
+	if len(changes) > 0 {
+		if err := checkRenderedPipelinesAreEqual(projectData, original, migrated, readFile); err != nil {
+			return nil, err
+		}
+		files = append(files, migratedFile{path: projectData.HalfpipeFilePath, original: original, migrated: migrated, changes: changes})
+	}
+
+	for _, cfManifest := range cfManifests(migrated, readFile) {
+		original, err := readFile(cfManifest)
+		if err != nil {
+			// the linter reports missing manifests
+			continue
+		}
+
+		migrated, changes, err := migrate.CFManifest(original)
+		if err != nil {
+			return nil, fmt.Errorf("could not migrate %s : %w", cfManifest, err)
+		}
+		if len(changes) > 0 {
+			files = append(files, migratedFile{path: cfManifest, original: original, migrated: migrated, changes: changes})
+		}
+	}
+
+	return files, nil
+}
+
+// checkRenderedPipelinesAreEqual makes sure that the migration does not change what the pipelines do
+func checkRenderedPipelinesAreEqual(projectData project.Data, original string, migrated string, readFile manifest.ReadFile) error {
+	originalPipelines, err := renderWithoutLinting(projectData, original, readFile)
+	if err != nil {
+		return fmt.Errorf("could not render %s, please fix it before migrating : %w", projectData.HalfpipeFilePath, err)
+	}
+
+	migratedPipelines, err := renderWithoutLinting(projectData, migrated, readFile)
+	if err != nil {
+		return fmt.Errorf("could not render the migrated %s : %w", projectData.HalfpipeFilePath, err)
+	}
+
+	for name, response := range originalPipelines {
+		if migratedPipelines[name].ConfigYaml != response.ConfigYaml {
+			return fmt.Errorf("the migration of %s would change the pipeline '%s', please migrate it by hand", projectData.HalfpipeFilePath, name)
+		}
+	}
+	return nil
+}
+
+// cfManifests returns the Cloud Foundry manifests of the deploy-cf tasks that are in the repo
+func cfManifests(manifestYaml string, readFile manifest.ReadFile) (paths []string) {
+	seen := map[string]bool{}
+	for _, document := range manifest.SplitDocuments(manifestYaml) {
+		man, _, _ := manifest.ParseWithIncludes("", document, readFile)
+		for _, task := range man.Tasks.Flatten() {
+			if deployCF, ok := task.(manifest.DeployCF); ok && deployCF.Manifest != "" && !strings.HasPrefix(deployCF.Manifest, "../artifacts/") && !seen[deployCF.Manifest] {
+				seen[deployCF.Manifest] = true
+				paths = append(paths, deployCF.Manifest)
+			}
+		}
+	}
+	return paths
+}
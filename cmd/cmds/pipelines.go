@@ -0,0 +1,197 @@
+package cmds
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/springernature/halfpipe"
+	"github.com/springernature/halfpipe/linters"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/project"
+)
+
+// pipeline is one of the pipelines to lint and render.
+// A repo can have a halfpipe manifest in several directories and a manifest can have several yaml documents.
+type pipeline struct {
+	dir            string
+	man            manifest.Manifest
+	manifestErrors []error
+	projectData    project.Data
+	controller     halfpipe.Controller
+}
+
+// getPipelines returns all pipelines in the halfpipe manifest of the current directory,
+// or with all set the pipelines in every halfpipe manifest in the git repo
+func getPipelines(halfpipeFilenameOptions []string, all bool) (pipelines []pipeline) {
+	if err := checkVersion(); err != nil {
+		printErr(err)
+		os.Exit(1)
+	}
+
+	fs := afero.Afero{Fs: afero.NewOsFs()}
+	projectResolver := project.NewProjectResolver(fs)
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		printErr(err)
+		os.Exit(1)
+	}
+	currentDir, halfpipeFilenameOptions = resolveInput(currentDir, halfpipeFilenameOptions)
+
+	dirs := []string{currentDir}
+	if all {
+		projectData, err := projectResolver.Parse(currentDir, true, halfpipeFilenameOptions)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+
+		dirs, err = projectResolver.FindHalfpipeFiles(projectData.GitRootPath, halfpipeFilenameOptions)
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+	}
+
+	for _, dir := range dirs {
+		projectData, err := projectResolver.Parse(dir, false, halfpipeFilenameOptions)
+		if err != nil {
+			printErr(fmt.Errorf("%s : %w", dir, err))
+			os.Exit(1)
+		}
+
+		manifests, err := getManifests(fs, dir, projectData.HalfpipeFilePath)
+		if err != nil {
+			pipelines = append(pipelines, pipeline{dir: dir, manifestErrors: []error{err}, projectData: projectData})
+			continue
+		}
+
+		for _, m := range manifests {
+			pipelines = append(pipelines, pipeline{
+				dir:            dir,
+				man:            m.man,
+				manifestErrors: m.errors,
+				projectData:    projectData,
+				controller:     createController(projectData, fs, dir, createRenderer(projectData, m.man), m.sourceMap),
+			})
+		}
+	}
+
+	return pipelines
+}
+
+// processPipelines lints all pipelines, and renders them when render is set, and outputs the lint results of all of them in one report.
+// If any of the pipelines has errors it exits before anything is written.
+func processPipelines(pipelines []pipeline, render bool) (responses []halfpipe.Response) {
+	var repoLintResults linters.RepoLintResults
+	pipelineFiles := map[string]string{}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		printErr(err)
+		os.Exit(1)
+	}
+	defer os.Chdir(currentDir) // nolint: errcheck
+
+	for _, p := range pipelines {
+		// the linters check files relative to the working directory
+		if err := os.Chdir(p.dir); err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+
+		response := halfpipe.Response{Project: p.projectData, Platform: p.man.Platform}
+		manifestFile := path.Join(p.projectData.BasePath, p.projectData.HalfpipeFilePath)
+
+		if len(p.manifestErrors) > 0 {
+			response.LintResults = manifestLintResults(p.manifestErrors)
+		} else {
+			var err error
+			if render {
+				response, err = p.controller.Process(p.man)
+			} else {
+				response = p.controller.Lint(p.man)
+			}
+			if err != nil {
+				printErr(fmt.Errorf("%s : %w", manifestFile, err))
+				os.Exit(1)
+			}
+
+			// the pipeline name is the name of the rendered file, so it must be unique in the repo
+			if other, found := pipelineFiles[p.man.PipelineName()]; found {
+				response.LintResults = append(response.LintResults, linters.NewLintResult("Pipelines", "https://ee.public.springernature.app/rel-eng/halfpipe/manifest/", []error{
+					fmt.Errorf("pipeline '%s' is also defined in %s, pipeline names must be unique in the repo", p.man.PipelineName(), other),
+				}))
+			}
+			pipelineFiles[p.man.PipelineName()] = manifestFile
+		}
+
+		repoLintResults = append(repoLintResults, linters.ManifestLintResults{
+			BasePath:     p.projectData.BasePath,
+			ManifestFile: manifestFile,
+			LintResults:  response.LintResults,
+		})
+		responses = append(responses, response)
+	}
+
+	outputRepoLintResults(repoLintResults)
+	return responses
+}
+
+func outputRepoLintResults(repoLintResults linters.RepoLintResults) {
+	var report []byte
+	var err error
+	switch OutputFormat {
+	case "":
+		for _, mlr := range repoLintResults {
+			if mlr.LintResults.HasErrors() || (mlr.LintResults.HasWarnings() && !Quiet) {
+				printErr(fmt.Errorf("%s\n%s", mlr.ManifestFile, mlr.LintResults))
+			}
+		}
+	case "json":
+		report, err = repoLintResults.JSON()
+	case "sarif":
+		report, err = repoLintResults.SARIF()
+	default:
+		err = fmt.Errorf("unknown output format '%s', must be one of 'json' or 'sarif'", OutputFormat)
+	}
+
+	if err != nil {
+		printErr(err)
+		os.Exit(1)
+	}
+
+	if report != nil {
+		fmt.Fprintln(lintResultsWriter, string(report)) // nolint: gas
+	}
+
+	if repoLintResults.HasErrors() {
+		os.Exit(1)
+	}
+}
+
+// writePipelines writes every pipeline to its own file.
+// Workflows are written to .github/workflows and Concourse pipelines to stdout, unless outputDir is set.
+func writePipelines(responses []halfpipe.Response, pipelines []pipeline, outputDir string) {
+	printed := false
+	for i, response := range responses {
+		pipelineName := pipelines[i].man.PipelineName()
+
+		var filePath string
+		switch {
+		case outputDir != "":
+			filePath = filepath.Join(outputDir, pipelineName+".yml")
+		case response.Platform.IsActions():
+			filePath = path.Join(response.Project.GitRootPath, ".github/workflows/", pipelineName+".yml")
+		case printed:
+			fmt.Println("---")
+		default:
+			printed = true
+		}
+
+		writePipeline(response, filePath)
+	}
+}
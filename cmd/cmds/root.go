@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 	"os"
 	"path"
+	"path/filepath"
 )
 
 var rootCmd = &cobra.Command{
@@ -11,7 +12,22 @@ var rootCmd = &cobra.Command{
 	Short: `halfpipe is a tool to lint and render pipelines
 Invoke without any arguments to lint your .halfpipe.io file and render a pipeline`,
 	Run: func(cmd *cobra.Command, args []string) {
-		man, controller := getManifestAndController(formatInput(Input), nil)
+		if output != "" {
+			// the input can be in another directory, which halfpipe changes to
+			output, _ = filepath.Abs(output)
+		}
+
+		pipelines := getPipelines(formatInput(Input), All)
+		if All || len(pipelines) > 1 {
+			writePipelines(processPipelines(pipelines, true), pipelines, output)
+			return
+		}
+
+		man, controller := pipelines[0].man, pipelines[0].controller
+		if len(pipelines[0].manifestErrors) > 0 {
+			outputLintResults(manifestLintResults(pipelines[0].manifestErrors), pipelines[0].projectData)
+		}
+
 		response, err := controller.Process(man)
 		if err != nil {
 			printErr(err)
@@ -29,7 +45,9 @@ Invoke without any arguments to lint your .halfpipe.io file and render a pipelin
 var output string
 
 func Execute() {
-	rootCmd.Flags().StringVarP(&output, "output", "o", "", "Sets the path where the rendered pipeline will be saved to")
+	rootCmd.Flags().StringVarP(&output, "output", "o", "", "Sets the path where the rendered pipeline will be saved to, or the directory when several pipelines are rendered")
+	rootCmd.Flags().BoolVar(&All, "all", false, "Lints and renders the pipelines of every halfpipe manifest in the git repo")
+	rootCmd.Flags().StringVar(&OutputFormat, "output-format", "", "Prints the lint results in this format, one of 'json' or 'sarif'")
 	if err := rootCmd.Execute(); err != nil {
 		printErr(err)
 		os.Exit(1)
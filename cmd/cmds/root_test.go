@@ -37,6 +37,8 @@ func TestE2EForCoverage(t *testing.T) {
 	config.CheckBranch = "false"
 	for _, testPath := range findE2EPaths() {
 		t.Run(testPath, func(t *testing.T) {
+			// rootCmd sets output to .github/workflows of the repo for actions manifests, so every dir gets its own
+			output = filepath.Join(t.TempDir(), "pipeline.yml")
 			os.Chdir(testPath)
 			rootCmd.Run(nil, []string{})
 		})
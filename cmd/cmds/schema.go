@@ -0,0 +1,27 @@
+package cmds
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/springernature/halfpipe/manifest"
+	"os"
+)
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Prints the JSON Schema of the halfpipe manifest",
+	Long: `Prints the JSON Schema (draft 2020-12) of the halfpipe manifest.
+Editors can use it to validate and autocomplete .halfpipe.io files`,
+	Run: func(cmd *cobra.Command, args []string) {
+		schema, err := manifest.JSONSchema()
+		if err != nil {
+			printErr(err)
+			os.Exit(1)
+		}
+		fmt.Println(string(schema))
+	},
+}
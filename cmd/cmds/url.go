@@ -26,13 +26,15 @@ var urlCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		projectData, err := project.NewProjectResolver(fs).Parse(currentDir, false, formatInput(Input))
+		currentDir, halfpipeFilenameOptions := resolveInput(currentDir, formatInput(Input))
+
+		projectData, err := project.NewProjectResolver(fs).Parse(currentDir, false, halfpipeFilenameOptions)
 		if err != nil {
 			printErr(err)
 			os.Exit(1)
 		}
 
-		man, _ := getManifest(fs, currentDir, projectData.HalfpipeFilePath)
+		man, _, _ := getManifest(fs, currentDir, projectData.HalfpipeFilePath)
 
 		if man.Platform.IsConcourse() {
 			fmt.Println(concourse.NewPipeline(projectData.HalfpipeFilePath).PlatformURL(man))
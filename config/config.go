@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/blang/semver"
 	"os"
+	"strings"
 )
 
 // These fields will be populated in build
@@ -21,12 +22,22 @@ var (
 
 	DockerRegistry = "eu.gcr.io/" + Project + "/"
 
+	// DockerRegistries is the allow-list of registries the organisation has credentials for, images in them are pulled and
+	// pushed without credentials in the manifest. Docker Hub is 'docker.io/'
+	DockerRegistries = strings.Split(getEnv("HALFPIPE_DOCKER_REGISTRIES", DockerRegistry), ",")
+
 	DockerComposeImage = "halfpipe-docker-compose:stable"
 
 	ConcourseURL = "https://concourse." + Domain
 
 	ActionsRunnerName = getEnv("HALFPIPE_ACTIONS_RUNNER", "ee-runner")
 
+	// ActionsRunnerLabels are the labels tasks are allowed to use in 'runs_on'
+	ActionsRunnerLabels = strings.Split(getEnv("HALFPIPE_ACTIONS_RUNNER_LABELS", ActionsRunnerName+",ee-runner-arm64,ee-runner-large"), ",")
+
+	// ActionsRepository is where the composite actions in /actions are used from, they are versioned with the release tags of halfpipe
+	ActionsRepository = getEnv("HALFPIPE_ACTIONS_REPOSITORY", GithubOrg+"/halfpipe")
+
 	CacheDirs = []string{
 		"../../../var/halfpipe/cache",
 		"../../../halfpipe-cache", // deprecated and should be removed after a while
@@ -69,6 +80,31 @@ func GetVersion() (semver.Version, error) {
 	return version, nil
 }
 
+// DockerRegistryHost returns the registry of the image, it is empty for images on Docker Hub.
+// Like in the docker cli the first part of the image is the registry when it contains a '.' or ':' or is 'localhost'
+func DockerRegistryHost(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return first
+	}
+	return ""
+}
+
+// IsAllowedDockerRegistry is true when the image is in one of the DockerRegistries
+func IsAllowedDockerRegistry(image string) bool {
+	// images without a registry are on Docker Hub
+	if DockerRegistryHost(image) == "" {
+		image = "docker.io/" + image
+	}
+
+	for _, registry := range DockerRegistries {
+		if registry != "" && strings.HasPrefix(image, registry) {
+			return true
+		}
+	}
+	return false
+}
+
 func getEnv(key string, defaultVal string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
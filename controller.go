@@ -10,6 +10,7 @@ import (
 
 type Controller interface {
 	Process(man manifest.Manifest) (Response, error)
+	Lint(man manifest.Manifest) Response
 	DefaultAndMap(man manifest.Manifest) (updated manifest.Manifest, err error)
 }
 
@@ -33,24 +34,40 @@ type controller struct {
 	mapper    mapper.Mapper
 	linters   []linters.Linter
 	renderer  Renderer
+	sourceMap manifest.SourceMap
 }
 
-func NewController(defaulter defaults.Defaults, mapper mapper.Mapper, linters []linters.Linter, renderer Renderer) Controller {
+func NewController(defaulter defaults.Defaults, mapper mapper.Mapper, linters []linters.Linter, renderer Renderer, sourceMap manifest.SourceMap) Controller {
 	return controller{
 		defaulter: defaulter,
 		mapper:    mapper,
 		linters:   linters,
 		renderer:  renderer,
+		sourceMap: sourceMap,
 	}
 }
 
-func (c controller) Process(man manifest.Manifest) (response Response, err error) {
-	defaultedManifest := c.defaulter.Apply(man)
+// Lint applies the defaults to the manifest and lints it, the manifest is not rendered
+func (c controller) Lint(man manifest.Manifest) Response {
+	response, _ := c.lint(man)
+	return response
+}
+
+func (c controller) lint(man manifest.Manifest) (response Response, defaultedManifest manifest.Manifest) {
+	response.Project = c.defaulter.Project
+	response.Platform = man.Platform
+
+	defaultedManifest = c.defaulter.Apply(man)
 
 	for _, linter := range c.linters {
 		response.LintResults = append(response.LintResults, linter.Lint(defaultedManifest))
 	}
+	response.LintResults = response.LintResults.WithSourceMap(c.sourceMap)
+	return response, defaultedManifest
+}
 
+func (c controller) Process(man manifest.Manifest) (response Response, err error) {
+	response, defaultedManifest := c.lint(man)
 	if response.LintResults.HasErrors() {
 		return
 	}
@@ -63,8 +80,6 @@ func (c controller) Process(man manifest.Manifest) (response Response, err error
 
 	config, err := c.renderer.Render(mappedManifest)
 	response.ConfigYaml = config
-	response.Project = c.defaulter.Project
-	response.Platform = man.Platform
 	return
 }
 
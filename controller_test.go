@@ -84,6 +84,15 @@ func TestGivesBackConfigWhenLinterPasses(t *testing.T) {
 	assert.Equal(t, "fake output", response.ConfigYaml)
 }
 
+func TestLintDoesNotRender(t *testing.T) {
+	c := testController()
+	c.linters = []linters.Linter{fakeLinter{linters.ErrFileNotFound.AsWarning()}}
+
+	response := c.Lint(validHalfpipeManifest)
+	assert.True(t, response.LintResults.HasWarnings())
+	assert.Empty(t, response.ConfigYaml)
+}
+
 type FakeMapper struct {
 	err error
 }
@@ -41,11 +41,15 @@ type KateeDefaults struct {
 }
 
 type DockerDefaults struct {
-	Username       string
-	Password       string
-	FilePath       string
-	ComposeFile    manifest.ComposeFiles
-	ComposeService string
+	Username string
+	Password string
+	// ContainerUsername and ContainerPassword are used for the job container of a run task in GitHub Actions,
+	// it is pulled before any step runs so the password must be a GitHub secret
+	ContainerUsername string
+	ContainerPassword string
+	FilePath          string
+	ComposeFile       manifest.ComposeFiles
+	ComposeService    string
 }
 
 type ArtifactoryDefaults struct {
@@ -86,6 +90,7 @@ type Defaults struct {
 
 func (d Defaults) Apply(original manifest.Manifest) (updated manifest.Manifest) {
 	updated = d.outputDefaulter.Apply(original)
+	updated.Tasks = updated.Tasks.ExpandMatrices()
 	updated.Triggers = d.triggersDefaulter.Apply(updated.Triggers, d, original)
 	updated.Tasks = d.tasksDefaulter.Apply(updated.Tasks, d, updated)
 	return updated
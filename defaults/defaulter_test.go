@@ -78,3 +78,38 @@ func TestApplyFeatureToggleDefaults(t *testing.T) {
 	}
 	assert.Equal(t, man.FeatureToggles, defaults.Apply(man).FeatureToggles)
 }
+
+func TestExpandsMatricesBeforeDefaultingTasks(t *testing.T) {
+	man, errs := manifest.Parse(`
+tasks:
+- type: run
+  name: test
+  matrix:
+    include:
+    - script: a.sh
+    - script: b.sh
+`)
+	assert.Empty(t, errs)
+
+	var tasksToDefault manifest.TaskList
+	defaults := Defaults{
+		triggersDefaulter: testTriggersDefaulter{apply: func(original manifest.TriggerList, defaults Defaults, man manifest.Manifest) (updated manifest.TriggerList) {
+			return original
+		}},
+		tasksDefaulter: testTasksDefaulter{apply: func(original manifest.TaskList, defaults Defaults, man manifest.Manifest) (updated manifest.TaskList) {
+			tasksToDefault = original
+			return original
+		}},
+		outputDefaulter: testOutputDefaulter{apply: func(original manifest.Manifest) (updated manifest.Manifest) {
+			return original
+		}},
+	}
+	defaults.Apply(man)
+
+	assert.Equal(t, manifest.TaskList{
+		manifest.Parallel{Tasks: manifest.TaskList{
+			manifest.Run{Name: "test (a.sh)", Script: "a.sh"},
+			manifest.Run{Name: "test (b.sh)", Script: "b.sh"},
+		}},
+	}, tasksToDefault)
+}
@@ -48,9 +48,11 @@ var Concourse = Defaults{
 var Actions = Defaults{
 	ShallowClone: true,
 	Docker: DockerDefaults{
-		ComposeService: "app",
-		ComposeFile:    []string{"docker-compose.yml"},
-		FilePath:       "Dockerfile",
+		ContainerUsername: "_json_key",
+		ContainerPassword: "${{ secrets.EE_GCR_PRIVATE_KEY }}",
+		ComposeService:    "app",
+		ComposeFile:       []string{"docker-compose.yml"},
+		FilePath:          "Dockerfile",
 	},
 
 	CF: CFDefaults{
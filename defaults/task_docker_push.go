@@ -10,12 +10,14 @@ import (
 func dockerPushDefaulter(original manifest.DockerPush, man manifest.Manifest, defaults Defaults) (updated manifest.DockerPush) {
 	updated = original
 
-	if man.Platform.IsConcourse() && strings.HasPrefix(updated.Image, config.DockerRegistry) {
+	// the images of a task are pushed to the same registry
+	if man.Platform.IsConcourse() && strings.HasPrefix(updated.ImageTasks()[0].Image, config.DockerRegistry) {
 		updated.Username = defaults.Docker.Username
 		updated.Password = defaults.Docker.Password
 	}
 
-	if updated.DockerfilePath == "" {
+	// the bake file defines the dockerfiles of its targets
+	if updated.DockerfilePath == "" && updated.BakeFile == "" {
 		updated.DockerfilePath = defaults.Docker.FilePath
 	}
 
@@ -23,6 +25,26 @@ func dockerPushDefaulter(original manifest.DockerPush, man manifest.Manifest, de
 		updated.ScanTimeout = 15
 	}
 
+	if updated.Scan.Severity == "" {
+		updated.Scan.Severity = "CRITICAL"
+	}
+
+	if updated.Scan.IgnoreUnfixed == nil {
+		ignoreUnfixed := true
+		updated.Scan.IgnoreUnfixed = &ignoreUnfixed
+	}
+
+	if updated.Scan.Mode == "" {
+		updated.Scan.Mode = manifest.ScanModeFail
+		if updated.IgnoreVulnerabilities {
+			updated.Scan.Mode = manifest.ScanModeWarn
+		}
+	}
+
+	if updated.Scan.IgnoreFile == "" {
+		updated.Scan.IgnoreFile = ".trivyignore"
+	}
+
 	if len(original.Platforms) == 0 {
 		updated.Platforms = []string{"linux/amd64"}
 	}
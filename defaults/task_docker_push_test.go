@@ -23,3 +23,18 @@ func TestPrivateImageSetsUsernameAndPassword(t *testing.T) {
 func TestSetsTheDockerFilePath(t *testing.T) {
 	assert.Equal(t, "Dockerfile", dockerPushDefaulter(manifest.DockerPush{}, manifest.Manifest{}, Concourse).DockerfilePath)
 }
+
+func TestSetsTheScan(t *testing.T) {
+	ignoreUnfixed := true
+	assert.Equal(t, manifest.Scan{Severity: "CRITICAL", IgnoreUnfixed: &ignoreUnfixed, Mode: "fail", IgnoreFile: ".trivyignore"}, dockerPushDefaulter(manifest.DockerPush{}, manifest.Manifest{}, Concourse).Scan)
+
+	t.Run("ignore_vulnerabilities only warns", func(t *testing.T) {
+		assert.Equal(t, "warn", dockerPushDefaulter(manifest.DockerPush{IgnoreVulnerabilities: true}, manifest.Manifest{}, Concourse).Scan.Mode)
+	})
+
+	t.Run("keeps the configured scan", func(t *testing.T) {
+		ignoreUnfixed := false
+		scan := manifest.Scan{Severity: "HIGH", IgnoreUnfixed: &ignoreUnfixed, Mode: "warn", IgnoreFile: "security/.trivyignore", Report: "sarif"}
+		assert.Equal(t, scan, dockerPushDefaulter(manifest.DockerPush{Scan: scan}, manifest.Manifest{}, Actions).Scan)
+	})
+}
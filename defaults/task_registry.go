@@ -0,0 +1,41 @@
+package defaults
+
+import "github.com/springernature/halfpipe/manifest"
+
+// TaskDefaulter applies the defaults to a task of the type it is registered for
+type TaskDefaulter func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task
+
+var taskDefaulters = map[string]TaskDefaulter{}
+
+// RegisterTaskDefaulter sets the defaulter for a type of task registered with manifest.RegisterTask.
+// Tasks of a type without a defaulter are left as they are.
+func RegisterTaskDefaulter(taskType string, defaulter TaskDefaulter) {
+	taskDefaulters[taskType] = defaulter
+}
+
+func init() {
+	RegisterTaskDefaulter("run", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return runDefaulter(task.(manifest.Run), defaults)
+	})
+	RegisterTaskDefaulter("docker-compose", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return dockerComposeDefaulter(task.(manifest.DockerCompose), defaults)
+	})
+	RegisterTaskDefaulter("docker-push", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return dockerPushDefaulter(task.(manifest.DockerPush), man, defaults)
+	})
+	RegisterTaskDefaulter("deploy-cf", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return deployCfDefaulter(task.(manifest.DeployCF), defaults, man)
+	})
+	RegisterTaskDefaulter("deploy-katee", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return deployKateeDefaulter(task.(manifest.DeployKatee), defaults, man)
+	})
+	RegisterTaskDefaulter("consumer-integration-test", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return consumerIntegration(task.(manifest.ConsumerIntegrationTest), defaults)
+	})
+	RegisterTaskDefaulter("deploy-ml-zip", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return deployMlZipDefaulter(task.(manifest.DeployMLZip), defaults)
+	})
+	RegisterTaskDefaulter("deploy-ml-modules", func(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+		return deployMlModuleDefaulter(task.(manifest.DeployMLModules), defaults)
+	})
+}
@@ -12,6 +12,10 @@ func runDefaulter(original manifest.Run, defaults Defaults) (updated manifest.Ru
 		updated.Docker.Username = defaults.Docker.Username
 		updated.Docker.Password = defaults.Docker.Password
 	}
+	if strings.HasPrefix(updated.Container.Image, config.DockerRegistry) {
+		updated.Container.Username = defaults.Docker.ContainerUsername
+		updated.Container.Password = defaults.Docker.ContainerPassword
+	}
 
 	return updated
 }
@@ -41,4 +41,21 @@ func TestRunTaskDockerDefault(t *testing.T) {
 		assert.Equal(t, expectedTask, runDefaulter(task, Concourse))
 	})
 
+	t.Run("with private container image", func(t *testing.T) {
+		task := manifest.Run{
+			Script:    "./blah",
+			Container: manifest.Docker{Image: config.DockerRegistry + "runImage"},
+		}
+
+		expectedTask := manifest.Run{
+			Script: "./blah",
+			Container: manifest.Docker{
+				Image:    config.DockerRegistry + "runImage",
+				Username: "_json_key",
+				Password: "${{ secrets.EE_GCR_PRIVATE_KEY }}",
+			},
+		}
+
+		assert.Equal(t, expectedTask, runDefaulter(task, Actions))
+	})
 }
@@ -15,14 +15,7 @@ type TasksEnvVarsDefaulter interface {
 }
 
 type tasksDefaulter struct {
-	runDefaulter                         func(original manifest.Run, defaults Defaults) (updated manifest.Run)
-	dockerComposeDefaulter               func(original manifest.DockerCompose, defaults Defaults) (updated manifest.DockerCompose)
-	dockerPushDefaulter                  func(original manifest.DockerPush, man manifest.Manifest, defaults Defaults) (updated manifest.DockerPush)
-	deployCfDefaulter                    func(original manifest.DeployCF, defaults Defaults, man manifest.Manifest) (updated manifest.DeployCF)
-	deployKateeDefaulter                 func(original manifest.DeployKatee, defaults Defaults, man manifest.Manifest) (updated manifest.DeployKatee)
-	consumerIntegrationTestTaskDefaulter func(original manifest.ConsumerIntegrationTest, defaults Defaults) (updated manifest.ConsumerIntegrationTest)
-	deployMlZipDefaulter                 func(original manifest.DeployMLZip, defaults Defaults) (updated manifest.DeployMLZip)
-	deployMlModulesDefaulter             func(original manifest.DeployMLModules, defaults Defaults) (updated manifest.DeployMLModules)
+	taskDefaulters map[string]TaskDefaulter
 
 	tasksRenamer          TasksRenamer
 	tasksTimeoutDefaulter TasksTimeoutDefaulter
@@ -31,14 +24,7 @@ type tasksDefaulter struct {
 
 func NewTaskDefaulter() TasksDefaulter {
 	return tasksDefaulter{
-		runDefaulter:                         runDefaulter,
-		dockerComposeDefaulter:               dockerComposeDefaulter,
-		dockerPushDefaulter:                  dockerPushDefaulter,
-		deployCfDefaulter:                    deployCfDefaulter,
-		deployKateeDefaulter:                 deployKateeDefaulter,
-		consumerIntegrationTestTaskDefaulter: consumerIntegration,
-		deployMlZipDefaulter:                 deployMlZipDefaulter,
-		deployMlModulesDefaulter:             deployMlModuleDefaulter,
+		taskDefaulters: taskDefaulters,
 
 		tasksRenamer:          NewTasksRenamer(),
 		tasksTimeoutDefaulter: NewTasksTimeoutDefaulter(),
@@ -55,31 +41,19 @@ func (t tasksDefaulter) Apply(original manifest.TaskList, defaults Defaults, man
 		switch task := task.(type) {
 		case manifest.Update:
 			tt = task
-		case manifest.Run:
-			tt = t.runDefaulter(task, defaults)
-		case manifest.DockerCompose:
-			tt = t.dockerComposeDefaulter(task, defaults)
-		case manifest.DockerPush:
-			tt = t.dockerPushDefaulter(task, man, defaults)
 		case manifest.DeployCF:
 			ppTasks := t.Apply(task.PrePromote, defaults, man)
-			task = t.deployCfDefaulter(task, defaults, man)
+			task = t.applyTaskDefaulter(task, defaults, man).(manifest.DeployCF)
 			task.PrePromote = ppTasks
 			tt = task
-		case manifest.DeployKatee:
-			tt = t.deployKateeDefaulter(task, defaults, man)
-		case manifest.ConsumerIntegrationTest:
-			tt = t.consumerIntegrationTestTaskDefaulter(task, defaults)
-		case manifest.DeployMLModules:
-			tt = t.deployMlModulesDefaulter(task, defaults)
-		case manifest.DeployMLZip:
-			tt = t.deployMlZipDefaulter(task, defaults)
 		case manifest.Parallel:
 			task.Tasks = t.Apply(task.Tasks, defaults, man)
 			tt = task
 		case manifest.Sequence:
 			task.Tasks = t.Apply(task.Tasks, defaults, man)
 			tt = task
+		default:
+			tt = t.applyTaskDefaulter(task, defaults, man)
 		}
 
 		tasksWithDefaultsApplied = append(tasksWithDefaultsApplied, tt)
@@ -90,3 +64,10 @@ func (t tasksDefaulter) Apply(original manifest.TaskList, defaults Defaults, man
 
 	return tasksWithEnvVarsApplied
 }
+
+func (t tasksDefaulter) applyTaskDefaulter(task manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+	if defaulter, found := t.taskDefaulters[manifest.TaskType(task)]; found {
+		return defaulter(task, defaults, man)
+	}
+	return task
+}
@@ -137,26 +137,28 @@ func TestCallsOutToTaskDefaultersCorrectly(t *testing.T) {
 			tasksEnvVarsDefaulterCalled = true
 			return original
 		}},
-		runDefaulter: func(original manifest.Run, defaults Defaults) (updated manifest.Run) {
-			return expectedRun
-		},
-		dockerComposeDefaulter: func(original manifest.DockerCompose, defaults Defaults) (updated manifest.DockerCompose) {
-			return expectedDockerCompose
-		},
-		dockerPushDefaulter: func(original manifest.DockerPush, man manifest.Manifest, defaults Defaults) (updated manifest.DockerPush) {
-			return expectedDockerPush
-		},
-		deployCfDefaulter: func(original manifest.DeployCF, defaults Defaults, man manifest.Manifest) (updated manifest.DeployCF) {
-			return expectedDeployCf
-		},
-		consumerIntegrationTestTaskDefaulter: func(original manifest.ConsumerIntegrationTest, defaults Defaults) (updated manifest.ConsumerIntegrationTest) {
-			return expectedConsumerTest
-		},
-		deployMlZipDefaulter: func(original manifest.DeployMLZip, defaults Defaults) (updated manifest.DeployMLZip) {
-			return expectedDeployMlZip
-		},
-		deployMlModulesDefaulter: func(original manifest.DeployMLModules, defaults Defaults) (updated manifest.DeployMLModules) {
-			return expectedDeployMlModules
+		taskDefaulters: map[string]TaskDefaulter{
+			"run": func(original manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+				return expectedRun
+			},
+			"docker-compose": func(original manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+				return expectedDockerCompose
+			},
+			"docker-push": func(original manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+				return expectedDockerPush
+			},
+			"deploy-cf": func(original manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+				return expectedDeployCf
+			},
+			"consumer-integration-test": func(original manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+				return expectedConsumerTest
+			},
+			"deploy-ml-zip": func(original manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+				return expectedDeployMlZip
+			},
+			"deploy-ml-modules": func(original manifest.Task, defaults Defaults, man manifest.Manifest) manifest.Task {
+				return expectedDeployMlModules
+			},
 		},
 	}
 
@@ -0,0 +1,13 @@
+package defaults
+
+import "github.com/springernature/halfpipe/manifest"
+
+func defaultPullRequestTrigger(original manifest.PullRequestTrigger, git manifest.GitTrigger) (updated manifest.PullRequestTrigger) {
+	updated = original
+
+	if len(updated.Branches) == 0 && git.Branch != "" {
+		updated.Branches = []string{git.Branch}
+	}
+
+	return updated
+}
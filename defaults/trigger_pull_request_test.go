@@ -0,0 +1,15 @@
+package defaults
+
+import (
+	"testing"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequestTriggerDefaultsToTheBranchOfTheGitTrigger(t *testing.T) {
+	git := manifest.GitTrigger{Branch: "main"}
+
+	assert.Equal(t, manifest.PullRequestTrigger{Branches: []string{"main"}}, defaultPullRequestTrigger(manifest.PullRequestTrigger{}, git))
+	assert.Equal(t, manifest.PullRequestTrigger{Branches: []string{"release/*"}}, defaultPullRequestTrigger(manifest.PullRequestTrigger{Branches: []string{"release/*"}}, git))
+}
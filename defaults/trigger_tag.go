@@ -0,0 +1,13 @@
+package defaults
+
+import "github.com/springernature/halfpipe/manifest"
+
+func defaultTagTrigger(original manifest.TagTrigger, defaults Defaults) (updated manifest.TagTrigger) {
+	updated = original
+
+	if updated.Pattern == "" {
+		updated.Pattern = "*"
+	}
+
+	return updated
+}
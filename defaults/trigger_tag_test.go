@@ -0,0 +1,13 @@
+package defaults
+
+import (
+	"testing"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagTriggerDefaultsToAllTags(t *testing.T) {
+	assert.Equal(t, manifest.TagTrigger{Pattern: "*"}, defaultTagTrigger(manifest.TagTrigger{}, Actions))
+	assert.Equal(t, manifest.TagTrigger{Pattern: "v*", Semver: true}, defaultTagTrigger(manifest.TagTrigger{Pattern: "v*", Semver: true}, Actions))
+}
@@ -10,6 +10,8 @@ type triggersDefaulter struct {
 	timerTriggerDefaulter    func(original manifest.TimerTrigger, defaults Defaults) (updated manifest.TimerTrigger)
 	pipelineTriggerDefaulter func(original manifest.PipelineTrigger, defaults Defaults, man manifest.Manifest) (updated manifest.PipelineTrigger)
 	dockerTriggerDefaulter   func(original manifest.DockerTrigger, defaults Defaults) (updated manifest.DockerTrigger)
+	tagTriggerDefaulter      func(original manifest.TagTrigger, defaults Defaults) (updated manifest.TagTrigger)
+	pullRequestDefaulter     func(original manifest.PullRequestTrigger, git manifest.GitTrigger) (updated manifest.PullRequestTrigger)
 }
 
 func NewTriggersDefaulter() TriggersDefaulter {
@@ -18,6 +20,8 @@ func NewTriggersDefaulter() TriggersDefaulter {
 		dockerTriggerDefaulter:   defaultDockerTrigger,
 		pipelineTriggerDefaulter: defaultPipelineTrigger,
 		gitTriggerDefaulter:      defaultGitTrigger,
+		tagTriggerDefaulter:      defaultTagTrigger,
+		pullRequestDefaulter:     defaultPullRequestTrigger,
 	}
 }
 
@@ -38,6 +42,17 @@ func (t triggersDefaulter) Apply(original manifest.TriggerList, defaults Default
 			updated = append(updated, t.pipelineTriggerDefaulter(trigger, defaults, man))
 		case manifest.DockerTrigger:
 			updated = append(updated, t.dockerTriggerDefaulter(trigger, defaults))
+		case manifest.TagTrigger:
+			updated = append(updated, t.tagTriggerDefaulter(trigger, defaults))
+		case manifest.PullRequestTrigger:
+			updated = append(updated, trigger)
+		}
+	}
+
+	// pull request triggers default to the git trigger, so they are defaulted after it
+	for i, trigger := range updated {
+		if pullRequest, ok := trigger.(manifest.PullRequestTrigger); ok {
+			updated[i] = t.pullRequestDefaulter(pullRequest, updated.GetGitTrigger())
 		}
 	}
 
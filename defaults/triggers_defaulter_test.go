@@ -82,3 +82,20 @@ func TestAddsDefaultGitTriggerIfThereIsntOneInTheTriggerList(t *testing.T) {
 
 	assert.Equal(t, expected, defaulter.Apply(input, Concourse, manifest.Manifest{}))
 }
+
+func TestPullRequestTriggersAreDefaultedWithTheDefaultedGitTrigger(t *testing.T) {
+	defaulter := triggersDefaulter{
+		gitTriggerDefaulter: func(original manifest.GitTrigger, defaults Defaults, branchResolver project.GitBranchResolver, platform manifest.Platform) (updated manifest.GitTrigger) {
+			original.Branch = "main"
+			return original
+		},
+		pullRequestDefaulter: defaultPullRequestTrigger,
+	}
+
+	updated := defaulter.Apply(manifest.TriggerList{manifest.PullRequestTrigger{}}, Actions, manifest.Manifest{})
+
+	assert.Equal(t, manifest.TriggerList{
+		manifest.PullRequestTrigger{Branches: []string{"main"}},
+		manifest.GitTrigger{Branch: "main"},
+	}, updated)
+}
@@ -0,0 +1,271 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// Change is a changed value in a job, resource or other item of a rendered pipeline.
+// Old is nil when the value was added and New is nil when it was removed.
+type Change struct {
+	Path string
+	Old  any
+	New  any
+}
+
+// Item is a job, resource or other item that is in both pipelines but has changed
+type Item struct {
+	Name    string
+	Changes []Change
+}
+
+// Section is a part of the rendered pipeline, e.g. the jobs or the resources, with the items that were added, removed or changed
+type Section struct {
+	Name    string
+	Added   []string
+	Removed []string
+	Changed []Item
+}
+
+func (s Section) isEmpty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.Changed) == 0
+}
+
+// Diff is the structural difference of two rendered pipelines
+type Diff []Section
+
+func (d Diff) IsEmpty() bool {
+	for _, section := range d {
+		if !section.isEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+func (d Diff) String() string {
+	if d.IsEmpty() {
+		return "No changes\n"
+	}
+
+	var out strings.Builder
+	for _, section := range d {
+		if section.isEmpty() {
+			continue
+		}
+		fmt.Fprintf(&out, "%s\n", section.Name)
+		for _, name := range section.Added {
+			fmt.Fprintf(&out, "  + %s\n", name)
+		}
+		for _, name := range section.Removed {
+			fmt.Fprintf(&out, "  - %s\n", name)
+		}
+		for _, item := range section.Changed {
+			fmt.Fprintf(&out, "  ~ %s\n", item.Name)
+			for _, change := range item.Changes {
+				switch {
+				case change.Old == nil:
+					fmt.Fprintf(&out, "      + %s: %s\n", change.Path, formatValue(change.New))
+				case change.New == nil:
+					fmt.Fprintf(&out, "      - %s: %s\n", change.Path, formatValue(change.Old))
+				default:
+					fmt.Fprintf(&out, "      ~ %s: %s -> %s\n", change.Path, formatValue(change.Old), formatValue(change.New))
+				}
+			}
+		}
+	}
+	return out.String()
+}
+
+// Concourse diffs two rendered Concourse pipelines by jobs, resources, resource types and groups
+func Concourse(oldYaml string, newYaml string) (Diff, error) {
+	return compare(oldYaml, newYaml, "pipeline", []string{"jobs", "resources", "resource_types", "groups"}, nil)
+}
+
+// Actions diffs two rendered GitHub Actions workflows by jobs
+func Actions(oldYaml string, newYaml string) (Diff, error) {
+	return compare(oldYaml, newYaml, "workflow", nil, []string{"jobs"})
+}
+
+// compare diffs the sections of the pipelines, namedLists are lists of items with a name and namedMaps are maps of name to item.
+// All other top level fields are compared as items of the rest section.
+func compare(oldYaml string, newYaml string, rest string, namedLists []string, namedMaps []string) (Diff, error) {
+	oldConfig, err := unmarshal(oldYaml)
+	if err != nil {
+		return nil, err
+	}
+	newConfig, err := unmarshal(newYaml)
+	if err != nil {
+		return nil, err
+	}
+
+	restOld, restNew := map[string]any{}, map[string]any{}
+	for key, value := range oldConfig {
+		if !slices.Contains(namedLists, key) && !slices.Contains(namedMaps, key) {
+			restOld[key] = value
+		}
+	}
+	for key, value := range newConfig {
+		if !slices.Contains(namedLists, key) && !slices.Contains(namedMaps, key) {
+			restNew[key] = value
+		}
+	}
+	d := Diff{compareItems(Section{Name: rest}, restOld, restNew)}
+	for _, name := range namedLists {
+		d = append(d, compareItems(Section{Name: name}, byName(oldConfig[name]), byName(newConfig[name])))
+	}
+	for _, name := range namedMaps {
+		oldItems, _ := oldConfig[name].(map[string]any)
+		newItems, _ := newConfig[name].(map[string]any)
+		d = append(d, compareItems(Section{Name: name}, oldItems, newItems))
+	}
+	return d, nil
+}
+
+func unmarshal(configYaml string) (config map[string]any, err error) {
+	if strings.TrimSpace(configYaml) == "" {
+		return map[string]any{}, nil
+	}
+	if err := yaml.Unmarshal([]byte(configYaml), &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func compareItems(section Section, oldItems map[string]any, newItems map[string]any) Section {
+	for _, name := range sortedKeys(newItems) {
+		if _, found := oldItems[name]; !found {
+			section.Added = append(section.Added, name)
+		}
+	}
+
+	for _, name := range sortedKeys(oldItems) {
+		newItem, found := newItems[name]
+		if !found {
+			section.Removed = append(section.Removed, name)
+			continue
+		}
+		if changes := compareValues("", oldItems[name], newItem); len(changes) > 0 {
+			section.Changed = append(section.Changed, Item{Name: name, Changes: changes})
+		}
+	}
+	return section
+}
+
+func compareValues(path string, oldValue any, newValue any) (changes []Change) {
+	oldMap, oldIsMap := oldValue.(map[string]any)
+	newMap, newIsMap := newValue.(map[string]any)
+	if oldIsMap && newIsMap {
+		for _, key := range sortedKeys(mergeKeys(oldMap, newMap)) {
+			changes = append(changes, compareValues(joinPath(path, key), oldMap[key], newMap[key])...)
+		}
+		return changes
+	}
+
+	oldList, oldIsList := oldValue.([]any)
+	newList, newIsList := newValue.([]any)
+	if oldIsList && newIsList {
+		oldItems, oldOrder := byIdentity(oldList)
+		newItems, newOrder := byIdentity(newList)
+		for _, id := range newOrder {
+			changes = append(changes, compareValues(fmt.Sprintf("%s[%s]", path, id), oldItems[id], newItems[id])...)
+		}
+		for _, id := range oldOrder {
+			if _, found := newItems[id]; !found {
+				changes = append(changes, Change{Path: fmt.Sprintf("%s[%s]", path, id), Old: oldItems[id]})
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+// identityKeys are the fields that identify an item in a list, e.g. a step in a Concourse plan or in a workflow job
+var identityKeys = []string{"name", "task", "get", "put", "id"}
+
+// byIdentity identifies the items in the list by the first identity key they have, or else by their index,
+// so that adding a step to a list only shows up as one change
+func byIdentity(list []any) (items map[string]any, order []string) {
+	items = map[string]any{}
+	for i, item := range list {
+		id := fmt.Sprint(i)
+		if fields, ok := item.(map[string]any); ok {
+			for _, key := range identityKeys {
+				if value, found := fields[key].(string); found {
+					id = fmt.Sprintf("%s: %s", key, value)
+					break
+				}
+			}
+		}
+		if _, duplicate := items[id]; duplicate {
+			id = fmt.Sprintf("%s #%d", id, i)
+		}
+		items[id] = item
+		order = append(order, id)
+	}
+	return items, order
+}
+
+func byName(list any) map[string]any {
+	items := map[string]any{}
+	l, _ := list.([]any)
+	for i, item := range l {
+		name := fmt.Sprint(i)
+		if fields, ok := item.(map[string]any); ok {
+			if n, found := fields["name"].(string); found {
+				name = n
+			}
+		}
+		items[name] = item
+	}
+	return items
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func mergeKeys(a map[string]any, b map[string]any) map[string]any {
+	merged := map[string]any{}
+	for key := range a {
+		merged[key] = nil
+	}
+	for key := range b {
+		merged[key] = nil
+	}
+	return merged
+}
+
+func sortedKeys(m map[string]any) (keys []string) {
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+const maxValueLength = 100
+
+func formatValue(value any) string {
+	formatted, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if len(formatted) > maxValueLength {
+		return string(formatted[:maxValueLength]) + "..."
+	}
+	return string(formatted)
+}
@@ -0,0 +1,216 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const concoursePipeline = `
+resources:
+- name: git
+  type: git
+  source:
+    uri: git@github.com:springernature/halfpipe.git
+- name: cf-live
+  type: cf-resource
+jobs:
+- name: test
+  plan:
+  - get: git
+    trigger: true
+  - task: test
+    config:
+      run:
+        path: ./test.sh
+- name: deploy
+  plan:
+  - get: git
+  - put: cf-live
+`
+
+func TestConcourseNoChanges(t *testing.T) {
+	d, err := Concourse(concoursePipeline, concoursePipeline)
+	assert.NoError(t, err)
+	assert.True(t, d.IsEmpty())
+	assert.Equal(t, "No changes\n", d.String())
+}
+
+func TestConcourseJobsAddedAndRemoved(t *testing.T) {
+	newPipeline := `
+resources:
+- name: git
+  type: git
+  source:
+    uri: git@github.com:springernature/halfpipe.git
+- name: cf-live
+  type: cf-resource
+jobs:
+- name: test
+  plan:
+  - get: git
+    trigger: true
+  - task: test
+    config:
+      run:
+        path: ./test.sh
+- name: smoke-test
+  plan:
+  - get: git
+`
+
+	d, err := Concourse(concoursePipeline, newPipeline)
+	assert.NoError(t, err)
+	jobs := d[1]
+	assert.Equal(t, "jobs", jobs.Name)
+	assert.Equal(t, []string{"smoke-test"}, jobs.Added)
+	assert.Equal(t, []string{"deploy"}, jobs.Removed)
+	assert.Empty(t, jobs.Changed)
+}
+
+func TestConcourseResourceChanged(t *testing.T) {
+	newPipeline := `
+resources:
+- name: git
+  type: git
+  source:
+    uri: git@github.com:springernature/halfpipe.git
+    branch: main
+- name: cf-live
+  type: cf-resource
+jobs:
+- name: test
+  plan:
+  - get: git
+    trigger: true
+  - task: test
+    config:
+      run:
+        path: ./test.sh
+- name: deploy
+  plan:
+  - get: git
+  - put: cf-live
+`
+
+	d, err := Concourse(concoursePipeline, newPipeline)
+	assert.NoError(t, err)
+	assert.Equal(t, []Item{{Name: "git", Changes: []Change{{Path: "source.branch", New: "main"}}}}, d[2].Changed)
+	assert.True(t, Diff{d[1]}.IsEmpty())
+}
+
+func TestConcourseStepsModified(t *testing.T) {
+	newPipeline := `
+resources:
+- name: git
+  type: git
+  source:
+    uri: git@github.com:springernature/halfpipe.git
+- name: cf-live
+  type: cf-resource
+jobs:
+- name: test
+  plan:
+  - get: git
+    trigger: true
+  - task: lint
+    config:
+      run:
+        path: ./lint.sh
+  - task: test
+    config:
+      run:
+        path: ./build/test.sh
+- name: deploy
+  plan:
+  - get: git
+  - put: cf-live
+`
+
+	d, err := Concourse(concoursePipeline, newPipeline)
+	assert.NoError(t, err)
+	assert.Equal(t, []Item{{Name: "test", Changes: []Change{
+		{Path: "plan[task: lint]", New: map[string]any{"task": "lint", "config": map[string]any{"run": map[string]any{"path": "./lint.sh"}}}},
+		{Path: "plan[task: test].config.run.path", Old: "./test.sh", New: "./build/test.sh"},
+	}}}, d[1].Changed)
+}
+
+func TestActionsJobsAndSteps(t *testing.T) {
+	oldWorkflow := `
+name: my-pipeline
+on:
+  push:
+    branches: [main]
+jobs:
+  test:
+    name: test
+    runs-on: ee-runner
+    steps:
+    - name: Checkout code
+      uses: actions/checkout@v3
+    - name: test
+      run: ./test.sh
+  deploy:
+    name: deploy
+    steps:
+    - name: Deploy
+      run: ./deploy.sh
+`
+	newWorkflow := `
+name: my-pipeline
+on:
+  push:
+    branches: [master]
+jobs:
+  test:
+    name: test
+    runs-on: ee-runner
+    steps:
+    - name: Checkout code
+      uses: actions/checkout@v4
+    - name: test
+      run: ./test.sh
+  build:
+    name: build
+    steps:
+    - name: Build
+      run: ./build.sh
+`
+
+	d, err := Actions(oldWorkflow, newWorkflow)
+	assert.NoError(t, err)
+	assert.Equal(t, Diff{
+		{
+			Name: "workflow",
+			Changed: []Item{{Name: "on", Changes: []Change{
+				{Path: "push.branches[0]", Old: "main", New: "master"},
+			}}},
+		},
+		{
+			Name:    "jobs",
+			Added:   []string{"build"},
+			Removed: []string{"deploy"},
+			Changed: []Item{{Name: "test", Changes: []Change{
+				{Path: "steps[name: Checkout code].uses", Old: "actions/checkout@v3", New: "actions/checkout@v4"},
+			}}},
+		},
+	}, d)
+
+	expected := `workflow
+  ~ on
+      ~ push.branches[0]: "main" -> "master"
+jobs
+  + build
+  - deploy
+  ~ test
+      ~ steps[name: Checkout code].uses: "actions/checkout@v3" -> "actions/checkout@v4"
+`
+	assert.Equal(t, expected, d.String())
+}
+
+func TestNewPipeline(t *testing.T) {
+	d, err := Concourse("", concoursePipeline)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cf-live", "git"}, d[2].Added)
+	assert.Equal(t, []string{"deploy", "test"}, d[1].Added)
+}
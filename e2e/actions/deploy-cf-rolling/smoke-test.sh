@@ -0,0 +1 @@
+asd
@@ -0,0 +1,8 @@
+target "app" {
+  dockerfile = "Dockerfile"
+  target     = "app"
+}
+
+target "migrations" {
+  context = "migrations"
+}
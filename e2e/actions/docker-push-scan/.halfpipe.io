@@ -0,0 +1,25 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-scan
+platform: actions
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/actions/docker-push-scan
+
+tasks:
+- type: docker-push
+  name: push with report
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/scanned
+  scan:
+    severity: HIGH
+    ignore_file: security/.trivyignore
+    report: sarif
+
+- type: docker-push
+  name: push only warning
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/warned
+  scan:
+    severity: MEDIUM
+    ignore_unfixed: false
+    mode: warn
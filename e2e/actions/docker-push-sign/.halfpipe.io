@@ -0,0 +1,22 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-sign
+platform: actions
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/actions/docker-push-sign
+
+tasks:
+- type: docker-push
+  name: push keyless
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/keyless
+  sbom: spdx
+  sign: true
+
+- type: docker-push
+  name: push with key
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/with-key
+  sbom: cyclonedx
+  signing_key: ((cosign.private_key))
+  signing_key_password: ((cosign.password))
@@ -0,0 +1,20 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-tags
+platform: actions
+
+triggers:
+- type: git
+  branch: main
+  watched_paths:
+  - e2e/actions/docker-push-tags
+
+tasks:
+- type: docker-push
+  name: push with tags
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/tagged
+  tags:
+  - stable
+  - v{version}
+  - v{major}
+  - v{minor}
+  - "{branch}-{gitref}"
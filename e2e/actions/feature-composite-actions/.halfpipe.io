@@ -0,0 +1,52 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+
+feature_toggles:
+- composite-actions
+
+tasks:
+- type: deploy-cf
+  name: deploy to dev
+  api: dev-api
+  space: dev
+  manifest: manifest.yml
+  username: michiel
+  password: ((cf.password))
+  test_domain: some.random.domain.com
+  vars:
+    A: "0.1"
+
+- type: deploy-cf
+  name: deploy to qa
+  api: dev-api
+  space: qa
+  manifest: manifest.yml
+  username: michiel
+  password: ((cf.password))
+  test_domain: some.random.domain.com
+  sso_route: my-route.public.springernature.app
+  pre_promote:
+  - type: run
+    name: smoke test
+    script: smoke-test.sh
+    docker:
+      image: alpine
+
+- type: deploy-cf
+  name: rolling deploy to live
+  api: live-api
+  space: live
+  manifest: manifest.yml
+  username: michiel
+  password: ((cf.password))
+  test_domain: some.random.domain.com
+  rolling: true
+
+- type: docker-push
+  name: push image
+  image: eu.gcr.io/halfpipe-io/someImage
+  use_cache: true
+  vars:
+    FOO: foo
+    SECRET: ((very.secret))
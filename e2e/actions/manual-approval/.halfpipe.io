@@ -0,0 +1,16 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+
+tasks:
+- type: run
+  name: test
+  script: \echo test
+  docker:
+    image: alpine
+- type: run
+  name: deploy to live
+  manual_trigger: true
+  script: \echo deploy
+  docker:
+    image: alpine
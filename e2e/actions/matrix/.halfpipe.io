@@ -0,0 +1,66 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+
+triggers:
+  - type: git
+    watched_paths:
+      - e2e/actions/matrix
+
+tasks:
+  - type: run
+    name: test
+    script: \echo test
+    docker:
+      image: alpine
+    matrix:
+      mode: sequence
+      include:
+        - vars:
+            JAVA_VERSION: 17
+        - vars:
+            JAVA_VERSION: 21
+
+  - type: deploy-cf
+    name: deploy
+    api: ((cloudfoundry.api-snpaas))
+    manifest: manifest.yml
+    vars:
+      ENV1: 1234
+    matrix:
+      include:
+        - space: qa
+        - space: staging
+          vars:
+            ENV2: staging
+    pre_promote:
+      - type: run
+        name: smoke test
+        script: \echo smoke test
+        docker:
+          image: alpine
+        matrix:
+          include:
+            - vars:
+                BROWSER: chrome
+            - vars:
+                BROWSER: firefox
+
+  - type: parallel
+    tasks:
+      - type: run
+        name: lint
+        script: \echo lint
+        docker:
+          image: alpine
+      - type: deploy-katee
+        name: deploy to katee
+        vela_manifest: vela.yaml
+        vars:
+          VERY_SECRET: ((secret.very))
+        matrix:
+          mode: sequence
+          include:
+            - namespace: katee-halfpipe-team-qa
+            - namespace: katee-halfpipe-team-live
+              environment: live
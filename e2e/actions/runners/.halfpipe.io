@@ -0,0 +1,27 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+
+tasks:
+- type: run
+  name: build on arm
+  script: build.sh
+  docker:
+    image: alpine
+  runs_on:
+  - ee-runner-arm64
+
+- type: run
+  name: build in container
+  script: ./build.sh
+  container:
+    image: eu.gcr.io/halfpipe-io/halfpipe-fly
+  vars:
+    A: a
+
+- type: docker-push
+  name: push on large runner
+  image: eu.gcr.io/halfpipe-io/someImage
+  runs_on:
+  - ee-runner
+  - ee-runner-large
@@ -0,0 +1,43 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+
+triggers:
+  - type: git
+    watched_paths:
+      - e2e/actions/templates
+
+templates:
+  deploy:
+    type: deploy-cf
+    api: ((cloudfoundry.api-snpaas))
+    org: ((cloudfoundry.org-snpaas))
+    space: dev
+    manifest: manifest.yml
+    vars:
+      ENV1: 1234
+      ENV2: ((secret.something))
+
+  deploy-with-smoke-test:
+    extends: deploy
+    pre_promote:
+      - type: run
+        name: smoke test
+        script: ./smoke-test.sh
+        docker:
+          image: ubuntu
+
+tasks:
+  - name: deploy to qa
+    extends: deploy-with-smoke-test
+    space: qa
+
+  - name: deploy to staging
+    extends: deploy-with-smoke-test
+    space: staging
+    vars:
+      ENV2: ((secret.staging))
+
+  - name: deploy to live
+    extends: deploy
+    space: live
@@ -0,0 +1,21 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+dispatch_job_status:
+- springernature/halfpipe-downstream
+
+triggers:
+- type: pipeline
+  pipeline: upstream-library
+  job: publish
+  repository: springernature/upstream-library
+- type: pipeline
+  pipeline: upstream-workflow
+  status: failed
+
+tasks:
+- type: run
+  name: test
+  script: \echo test
+  docker:
+    image: alpine
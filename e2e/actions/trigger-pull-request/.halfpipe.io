@@ -0,0 +1,36 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+
+feature_toggles:
+- update-pipeline
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/actions/trigger-pull-request
+- type: pull_request
+  branches:
+  - main
+  types:
+  - opened
+  - synchronize
+
+tasks:
+- type: run
+  name: test
+  script: \echo test
+  docker:
+    image: alpine
+- type: docker-push
+  name: push pull request image
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/someImage-pr
+  skip_on_pull_request: false
+- type: run
+  name: release notes
+  script: \echo release notes
+  docker:
+    image: alpine
+  skip_on_pull_request: true
+- type: docker-push
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/someImage
@@ -0,0 +1,15 @@
+team: halfpipe-team
+pipeline: pipeline-name
+platform: actions
+
+triggers:
+- type: tag
+  pattern: v*
+  semver: true
+
+tasks:
+- type: run
+  name: publish
+  script: \echo publish $BUILD_VERSION
+  docker:
+    image: alpine
@@ -0,0 +1,28 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-cache
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/concourse/cache
+
+tasks:
+- type: run
+  name: build
+  script: build.sh
+  docker:
+    image: eu.gcr.io/halfpipe-io/gradle:8
+  cache:
+    paths:
+    - .gradle/caches
+    - .gradle/wrapper
+    key_files:
+    - build.gradle
+
+- type: docker-compose
+  name: test
+  cache:
+    paths:
+    - node_modules
+    key_files:
+    - package-lock.json
@@ -0,0 +1,21 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-bake
+platform: concourse
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/concourse/docker-push-bake
+
+tasks:
+- type: docker-push
+  name: push images
+  bake_file: docker-bake.hcl
+  use_cache: true
+  vars:
+    A: a
+  images:
+  - image: eu.gcr.io/halfpipe-io/halfpipe-team/app
+    target: app
+  - image: eu.gcr.io/halfpipe-io/halfpipe-team/migrations
+    target: migrations
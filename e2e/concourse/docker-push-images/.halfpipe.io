@@ -0,0 +1,21 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-images
+platform: concourse
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/concourse/docker-push-images
+
+tasks:
+- type: docker-push
+  name: push images
+  use_cache: true
+  images:
+  - image: eu.gcr.io/halfpipe-io/halfpipe-team/app
+    target: app
+  - image: eu.gcr.io/halfpipe-io/halfpipe-team/worker
+    target: worker
+  - image: eu.gcr.io/halfpipe-io/halfpipe-team/migrations
+    dockerfile_path: migrations/Dockerfile
+    build_path: migrations
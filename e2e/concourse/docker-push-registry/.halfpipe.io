@@ -0,0 +1,17 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-registry
+platform: concourse
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/concourse/docker-push-registry
+
+tasks:
+- type: docker-push
+  name: push to harbor
+  image: harbor.example.com/halfpipe-team/app
+  username: ((harbor.username))
+  password: ((harbor.password))
+  sign: true
+  signing_key: ((cosign.private_key))
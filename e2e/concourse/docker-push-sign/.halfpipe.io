@@ -0,0 +1,22 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-sign
+platform: concourse
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/concourse/docker-push-sign
+
+tasks:
+- type: docker-push
+  name: push signed
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/signed
+  sign: true
+  signing_key: ((cosign.private_key))
+
+- type: docker-push
+  name: push with sbom
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/with-sbom
+  sbom: cyclonedx
+  signing_key: ((cosign.private_key))
+  signing_key_password: ((cosign.password))
@@ -0,0 +1,23 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-docker-push-tags
+platform: concourse
+
+feature_toggles:
+- update-pipeline
+
+triggers:
+- type: git
+  branch: main
+  watched_paths:
+  - e2e/concourse/docker-push-tags
+
+tasks:
+- type: docker-push
+  name: push with tags
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/tagged
+  tags:
+  - stable
+  - v{version}
+  - v{major}
+  - v{minor}
+  - "{branch}-{gitref}"
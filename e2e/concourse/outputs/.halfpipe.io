@@ -0,0 +1,32 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-outputs
+platform: concourse
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/concourse/outputs
+
+tasks:
+- type: run
+  name: Build App
+  script: build.sh
+  docker:
+    image: eu.gcr.io/halfpipe-io/gradle:8
+  outputs:
+    version: build/version
+    digest: build/digest
+
+- type: docker-compose
+  name: test
+  vars:
+    VERSION: v${{ outputs.build_app.version }}
+
+- type: run
+  name: publish
+  script: publish.sh
+  docker:
+    image: alpine
+  vars:
+    IMAGE: eu.gcr.io/halfpipe-io/app@${{ outputs.build_app.digest }}
+    VERSION: ${{ outputs.build_app.version }}
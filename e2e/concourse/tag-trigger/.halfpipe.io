@@ -0,0 +1,27 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-tag-trigger
+
+triggers:
+- type: tag
+  pattern: v*
+  semver: true
+
+tasks:
+- type: run
+  name: publish
+  script: ./publish.sh
+  docker:
+    image: alpine
+
+- type: docker-push
+  name: push
+  image: eu.gcr.io/halfpipe-io/halfpipe-team/tag-trigger
+
+- type: deploy-cf
+  name: deploy
+  api: dev-api
+  space: dev
+  manifest: manifest.yml
+  username: michiel
+  password: very-secret
+  test_domain: some.random.domain.com
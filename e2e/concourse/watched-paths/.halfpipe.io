@@ -0,0 +1,38 @@
+team: halfpipe-team
+pipeline: halfpipe-e2e-watched-paths
+platform: concourse
+
+triggers:
+- type: git
+  watched_paths:
+  - e2e/concourse/watched-paths
+
+tasks:
+- type: run
+  name: test
+  script: build.sh
+  docker:
+    image: alpine
+
+- type: parallel
+  tasks:
+  - type: docker-push
+    name: push a
+    image: eu.gcr.io/halfpipe-io/halfpipe-team/service-a
+    dockerfile_path: services/a/Dockerfile
+    build_path: services/a
+    watched_paths:
+    - e2e/concourse/watched-paths/services/a
+  - type: docker-push
+    name: push b
+    image: eu.gcr.io/halfpipe-io/halfpipe-team/service-b
+    dockerfile_path: services/b/Dockerfile
+    build_path: services/b
+    watched_paths:
+    - e2e/concourse/watched-paths/services/b
+
+- type: run
+  name: smoke test
+  script: build.sh
+  docker:
+    image: alpine
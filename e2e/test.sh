@@ -28,9 +28,26 @@ runTest() {
 rm -rf /tmp/halfpipe-e2e
 export -f runTest
 
+# the rendered pipelines must never end up in the workflows of this repo
+workflowsBefore=$(git status --porcelain ../.github/workflows)
+
 if command -v parallel > /dev/null; then
   ls -d */*/ | parallel -j16 runTest
 else
   # xargs doesn't return exit code reliably with parallel
   ls -d */*/ | xargs -ID -P1 bash -c "runTest D"
 fi
+status=$?
+
+if [[ "$(git status --porcelain ../.github/workflows)" != "$workflowsBefore" ]]; then
+  echo "e2e tests wrote to .github/workflows:"
+  git status --porcelain ../.github/workflows
+  exit 1
+fi
+
+# also catches rendered pipelines that have been committed
+if grep -l "^# Generated using halfpipe cli .* from file e2e/" ../.github/workflows/*.yml; then
+  echo "e2e pipelines must not be in .github/workflows"
+  exit 1
+fi
+exit $status
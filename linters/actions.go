@@ -2,8 +2,11 @@ package linters
 
 import (
 	"fmt"
+
+	"github.com/springernature/halfpipe/config"
 	"github.com/springernature/halfpipe/manifest"
 	"github.com/springernature/halfpipe/project"
+	"golang.org/x/exp/slices"
 )
 
 type actionsLinter struct {
@@ -21,6 +24,7 @@ func (linter actionsLinter) Lint(man manifest.Manifest) (result LintResult) {
 		result.Add(unsupportedTasks(man.Tasks, man, "tasks")...)
 		result.Add(linter.unsupportedTriggers(man.Triggers)...)
 		result.Add(unsupportedFeatures(man.FeatureToggles)...)
+		result.Add(dispatchJobStatus(man.DispatchJobStatus)...)
 	}
 	return result
 }
@@ -30,7 +34,7 @@ func unsupportedTasks(tasks manifest.TaskList, man manifest.Manifest, taskListId
 		taskIdx := fmt.Sprintf("%s[%v]", taskListId, i)
 
 		appendError := func(err error) {
-			errors = append(errors, fmt.Errorf("%s %w", taskIdx, err))
+			errors = append(errors, errorAt(taskIdx, err))
 		}
 
 		switch task := t.(type) {
@@ -40,20 +44,23 @@ func unsupportedTasks(tasks manifest.TaskList, man manifest.Manifest, taskListId
 			errors = append(errors, unsupportedTasks(task.Tasks, man, taskIdx)...)
 		default:
 			if task.IsManualTrigger() {
-				appendError(ErrUnsupportedManualTrigger.AsWarning())
+				appendError(ErrManualTriggerEnvironment.WithValue(task.GetName()).AsWarning())
+			}
+			for _, label := range task.GetRunsOn() {
+				if !slices.Contains(config.ActionsRunnerLabels, label) {
+					appendError(ErrUnsupportedRunnerLabel.WithValue(label))
+				}
 			}
 		}
 
 		switch task := t.(type) {
-		case manifest.DeployCF:
-			if task.Rolling {
-				appendError(ErrUnsupportedRolling.AsWarning())
-			}
 		case manifest.DockerPush:
-			for _, trigger := range man.Triggers {
-				if t, ok := trigger.(manifest.DockerTrigger); ok {
-					if t.Image == task.Image {
-						appendError(ErrDockerTriggerLoop.WithValue(t.Image).AsWarning())
+			for _, imageTask := range task.ImageTasks() {
+				for _, trigger := range man.Triggers {
+					if t, ok := trigger.(manifest.DockerTrigger); ok {
+						if t.Image == imageTask.Image {
+							appendError(ErrDockerTriggerLoop.WithValue(t.Image).AsWarning())
+						}
 					}
 				}
 			}
@@ -66,7 +73,7 @@ func unsupportedTasks(tasks manifest.TaskList, man manifest.Manifest, taskListId
 func (linter actionsLinter) unsupportedTriggers(triggers manifest.TriggerList) (errors []error) {
 	for i, trigger := range triggers {
 		appendError := func(err error) {
-			errors = append(errors, fmt.Errorf("triggers[%v] %w", i, err))
+			errors = append(errors, errorAt(fmt.Sprintf("triggers[%v]", i), err))
 		}
 
 		switch t := trigger.(type) {
@@ -83,8 +90,6 @@ func (linter actionsLinter) unsupportedTriggers(triggers manifest.TriggerList) (
 			if t.URI != "" && t.URI != resolvedUri {
 				appendError(ErrUnsupportedGitUri.AsWarning())
 			}
-		case manifest.PipelineTrigger:
-			appendError(ErrUnsupportedPipelineTrigger.AsWarning())
 		default:
 			// ok
 		}
@@ -92,6 +97,15 @@ func (linter actionsLinter) unsupportedTriggers(triggers manifest.TriggerList) (
 	return errors
 }
 
+func dispatchJobStatus(repositories []string) (errors []error) {
+	for i, repository := range repositories {
+		if !githubRepository.MatchString(repository) {
+			errors = append(errors, NewErrInvalidField(fmt.Sprintf("dispatch_job_status[%d]", i), "must be a GitHub repository as 'owner/name'"))
+		}
+	}
+	return errors
+}
+
 func unsupportedFeatures(features manifest.FeatureToggles) (errors []error) {
 	return []error{}
 }
@@ -2,6 +2,7 @@ package linters
 
 import (
 	"errors"
+	"github.com/springernature/halfpipe/config"
 	"github.com/springernature/halfpipe/manifest"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -11,7 +12,7 @@ var emptyResolver = func() (string, error) {
 	return "", nil
 }
 
-func TestActionsLinter_UnsupportedTriggers(t *testing.T) {
+func TestActionsLinter_SupportedTriggers(t *testing.T) {
 	man := manifest.Manifest{
 		Platform: "actions",
 		Triggers: manifest.TriggerList{
@@ -23,7 +24,7 @@ func TestActionsLinter_UnsupportedTriggers(t *testing.T) {
 	}
 
 	errs := NewActionsLinter(emptyResolver).Lint(man).Issues
-	assertContainsError(t, errs, ErrUnsupportedPipelineTrigger)
+	assert.Empty(t, errs)
 }
 
 func TestActionsLinter_UnsupportedGitTriggerOptions(t *testing.T) {
@@ -104,12 +105,30 @@ func TestActionsLinter_UnsupportedTaskOptions(t *testing.T) {
 	}
 	errs := NewActionsLinter(emptyResolver).Lint(man).Issues
 
-	if assert.Len(t, errs, 4) {
-		assert.Contains(t, errs[0].Error(), "manual_trigger")
-		assert.Contains(t, errs[1].Error(), "manual_trigger")
-		assert.Contains(t, errs[2].Error(), "manual_trigger")
-		assert.Contains(t, errs[3].Error(), "rolling")
+	// manual_trigger is supported with GitHub environments, but they must be given reviewers
+	if assert.Len(t, errs, 3) {
+		for _, err := range errs {
+			assert.ErrorIs(t, err, ErrManualTriggerEnvironment)
+			assert.Contains(t, err.Error(), "halfpipe environments")
+		}
+	}
+}
+
+func TestActionsLinter_RunnerLabels(t *testing.T) {
+	man := manifest.Manifest{
+		Platform: "actions",
+		Tasks: manifest.TaskList{
+			manifest.DockerPush{RunsOn: []string{config.ActionsRunnerLabels[0]}},
+			manifest.Parallel{Tasks: manifest.TaskList{
+				manifest.Run{RunsOn: []string{"self-hosted", "gpu"}},
+			}},
+		},
 	}
+
+	errs := NewActionsLinter(emptyResolver).Lint(man).Issues
+	assert.Len(t, errs, 2)
+	assertContainsError(t, errs, ErrUnsupportedRunnerLabel.WithValue("self-hosted"))
+	assertContainsError(t, errs, ErrUnsupportedRunnerLabel.WithValue("gpu"))
 }
 
 func TestActionsLinter_PreventCircularTriggers(t *testing.T) {
@@ -130,3 +149,14 @@ func TestActionsLinter_PreventCircularTriggers(t *testing.T) {
 	errs := NewActionsLinter(emptyResolver).Lint(man).Issues
 	assertContainsError(t, errs, ErrDockerTriggerLoop)
 }
+
+func TestActionsLinter_DispatchJobStatus(t *testing.T) {
+	man := manifest.Manifest{
+		Platform:          "actions",
+		DispatchJobStatus: []string{"springernature/halfpipe", "halfpipe"},
+	}
+
+	errs := NewActionsLinter(emptyResolver).Lint(man).Issues
+	assert.Len(t, errs, 1)
+	assertContainsError(t, errs, ErrInvalidField.WithValue("dispatch_job_status[1]"))
+}
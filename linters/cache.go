@@ -0,0 +1,29 @@
+package linters
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/springernature/halfpipe/manifest"
+)
+
+// LintCache checks that the cached paths and the key files are inside the repo, basePath is the path of the manifest in the repo
+func LintCache(cache manifest.Cache, basePath string) (errs []error) {
+	if len(cache.KeyFiles) > 0 && len(cache.Paths) == 0 {
+		errs = append(errs, NewErrMissingField("cache.paths"))
+	}
+
+	lintPaths := func(field string, paths []string) {
+		for _, p := range paths {
+			inRepo := path.Clean(path.Join(basePath, p))
+			if path.IsAbs(p) || inRepo == "." || inRepo == ".." || strings.HasPrefix(inRepo, "../") {
+				errs = append(errs, NewErrInvalidField(field, fmt.Sprintf("'%s' must be inside the repository", p)))
+			}
+		}
+	}
+	lintPaths("cache.paths", cache.Paths)
+	lintPaths("cache.key_files", cache.KeyFiles)
+
+	return errs
+}
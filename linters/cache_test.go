@@ -0,0 +1,33 @@
+package linters
+
+import (
+	"testing"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("paths inside the repo", func(t *testing.T) {
+		cache := manifest.Cache{
+			Paths:    []string{".gradle/caches", "../shared/node_modules"},
+			KeyFiles: []string{"build.gradle", "../shared/package-lock.json"},
+		}
+		assert.Empty(t, LintCache(cache, "apps/app"))
+	})
+
+	t.Run("paths outside the repo", func(t *testing.T) {
+		cache := manifest.Cache{
+			Paths:    []string{"/root/.gradle", "../../node_modules", ".."},
+			KeyFiles: []string{"../../package-lock.json"},
+		}
+		errs := LintCache(cache, "app")
+		assert.Len(t, errs, 4)
+		assertContainsError(t, errs, ErrInvalidField.WithValue("cache.paths"))
+		assertContainsError(t, errs, ErrInvalidField.WithValue("cache.key_files"))
+	})
+
+	t.Run("key files without paths", func(t *testing.T) {
+		assertContainsError(t, LintCache(manifest.Cache{KeyFiles: []string{"build.gradle"}}, ""), NewErrMissingField("cache.paths"))
+	})
+}
@@ -69,7 +69,7 @@ func lintDockerPush(task manifest.DeployCF, app manifestparser.Application) (err
 			return
 		}
 
-		if !strings.HasPrefix(app.Docker.Image, config.DockerRegistry) {
+		if !config.IsAllowedDockerRegistry(app.Docker.Image) {
 			errs = append(errs, ErrUnsupportedRegistry.WithValue(app.Docker.Image).WithFile(task.Manifest))
 			return
 		}
@@ -198,6 +198,23 @@ applications:
 		assertContainsError(t, errs, ErrUnsupportedRegistry)
 	})
 
+	t.Run("when the image is from a registry in the allow-list", func(t *testing.T) {
+		registries := config.DockerRegistries
+		defer func() { config.DockerRegistries = registries }()
+		config.DockerRegistries = []string{config.DockerRegistry, "ghcr.io/springernature/"}
+
+		cfManifest := `
+applications:
+- name: test
+  docker:
+    image: ghcr.io/springernature/blah
+  routes:
+  - route: test.com
+`
+		errs := LintCfManifest(manifest.DeployCF{}, cfManifestReader(cfManifest, nil))
+		assertNotContainsError(t, errs, ErrUnsupportedRegistry)
+	})
+
 	t.Run("All is good", func(t *testing.T) {
 		cfManifest := fmt.Sprintf(`
 applications:
@@ -18,8 +18,8 @@ func LintDeployKateeTask(task manifest.DeployKatee, man manifest.Manifest, fs af
 		}
 	}
 
-	if task.Tag == "version" && man.Platform.IsConcourse() && !man.FeatureToggles.UpdatePipeline() {
-		errs = append(errs, NewErrInvalidField("tag", "'version' requires the 'update-pipeline' feature toggle"))
+	if task.Tag == "version" && man.Platform.IsConcourse() && !man.FeatureToggles.UpdatePipeline() && !man.Triggers.HasTagTrigger() {
+		errs = append(errs, NewErrInvalidField("tag", "'version' requires the 'update-pipeline' feature toggle or a tag trigger"))
 	}
 
 	// Check platform_version
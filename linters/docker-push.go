@@ -8,10 +8,49 @@ import (
 	"strings"
 
 	"github.com/spf13/afero"
+	"github.com/springernature/halfpipe/config"
 	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/renderers/shared"
 )
 
-func LintDockerPushTask(docker manifest.DockerPush, fs afero.Afero) (errs []error) {
+func LintDockerPushTask(docker manifest.DockerPush, platform manifest.Platform, fs afero.Afero) (errs []error) {
+	if len(docker.Images) == 0 {
+		errs = append(errs, lintDockerImage(docker, fs)...)
+	} else {
+		errs = append(errs, lintDockerImages(docker, fs)...)
+	}
+
+	if (docker.Username == "") != (docker.Password == "") {
+		errs = append(errs, NewErrInvalidField("username", "username and password must be set together"))
+	}
+
+	if docker.Retries < 0 || docker.Retries > 5 {
+		errs = append(errs, NewErrInvalidField("retries", "must be between 0 and 5"))
+	}
+
+	if docker.Tag != "" {
+		errs = append(errs, ErrDockerPushTag.AsWarning())
+	}
+
+	for _, platform := range docker.Platforms {
+		if !slices.Contains([]string{"linux/amd64", "linux/arm64"}, platform) {
+			errs = append(errs, ErrDockerPlatformUnknown)
+		}
+	}
+
+	for k, v := range docker.Vars {
+		if strings.HasPrefix(v, "((") && strings.HasSuffix(v, "))") && !strings.HasPrefix(k, "ARTIFACTORY_") {
+			errs = append(errs, ErrDockerVarSecret.WithValue(k).AsWarning())
+		}
+	}
+
+	errs = append(errs, lintScan(docker)...)
+	errs = append(errs, lintSupplyChain(docker, platform)...)
+
+	return errs
+}
+
+func lintDockerImage(docker manifest.DockerPush, fs afero.Afero) (errs []error) {
 	if docker.Image == "" {
 		errs = append(errs, NewErrMissingField("image"))
 	} else {
@@ -30,8 +69,12 @@ func LintDockerPushTask(docker manifest.DockerPush, fs afero.Afero) (errs []erro
 		}
 	}
 
-	if docker.Retries < 0 || docker.Retries > 5 {
-		errs = append(errs, NewErrInvalidField("retries", "must be between 0 and 5"))
+	if docker.Image != "" && !config.IsAllowedDockerRegistry(docker.Image) && docker.Username == "" {
+		errs = append(errs, NewErrInvalidField("image", "is not in one of the registries of the organisation (HALFPIPE_DOCKER_REGISTRIES), set username and password to log in to its registry").AsWarning())
+	}
+
+	if docker.BakeFile != "" {
+		return append(errs, lintBakeFile(docker, fs)...)
 	}
 
 	if docker.DockerfilePath == "" {
@@ -58,19 +101,151 @@ func LintDockerPushTask(docker manifest.DockerPush, fs afero.Afero) (errs []erro
 		}
 	}
 
-	if docker.Tag != "" {
-		errs = append(errs, ErrDockerPushTag.AsWarning())
+	return errs
+}
+
+func lintBakeFile(docker manifest.DockerPush, fs afero.Afero) (errs []error) {
+	if docker.DockerfilePath != "" {
+		errs = append(errs, NewErrInvalidField("dockerfile_path", "cannot be used together with bake_file, the bake file defines the Dockerfile of the target"))
 	}
 
-	for _, platform := range docker.Platforms {
-		if !slices.Contains([]string{"linux/amd64", "linux/arm64"}, platform) {
-			errs = append(errs, ErrDockerPlatformUnknown)
+	if docker.BuildPath != "" {
+		errs = append(errs, NewErrInvalidField("build_path", "cannot be used together with bake_file, the bake file defines the context of the target"))
+	}
+
+	if !docker.RestoreArtifacts {
+		if _, err := ReadFile(fs, docker.BakeFile); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	for k, v := range docker.Vars {
-		if strings.HasPrefix(v, "((") && strings.HasSuffix(v, "))") && !strings.HasPrefix(k, "ARTIFACTORY_") {
-			errs = append(errs, ErrDockerVarSecret.WithValue(k).AsWarning())
+	return errs
+}
+
+func lintDockerImages(docker manifest.DockerPush, fs afero.Afero) (errs []error) {
+	if docker.Image != "" {
+		errs = append(errs, NewErrInvalidField("image", "cannot be used together with images"))
+	}
+
+	if docker.Target != "" {
+		errs = append(errs, NewErrInvalidField("target", "cannot be used together with images, set the target of each image instead"))
+	}
+
+	if docker.Scan.Report != "" {
+		errs = append(errs, NewErrInvalidField("scan.report", "cannot be used together with images"))
+	}
+
+	// the registry is logged in to once for all the images
+	registry := config.DockerRegistryHost
+
+	var images []string
+	for i, task := range docker.ImageTasks() {
+		for _, err := range lintDockerImage(task, fs) {
+			errs = append(errs, errorAt(fmt.Sprintf("images[%d]", i), err))
+		}
+
+		if slices.Contains(images, task.Image) {
+			errs = append(errs, errorAt(fmt.Sprintf("images[%d]", i), NewErrInvalidField("image", fmt.Sprintf("'%s' is listed more than once", task.Image))))
+		} else if len(images) > 0 && registry(task.Image) != registry(images[0]) {
+			errs = append(errs, errorAt(fmt.Sprintf("images[%d]", i), NewErrInvalidField("image", "must be in the same registry as the other images")))
+		}
+		images = append(images, task.Image)
+	}
+
+	return errs
+}
+
+func lintScan(docker manifest.DockerPush) (errs []error) {
+	scan := docker.Scan
+
+	if scan.Severity != "" && !slices.Contains(manifest.ScanSeverities, scan.Severity) {
+		errs = append(errs, NewErrInvalidField("scan.severity", fmt.Sprintf("must be one of %s", strings.Join(manifest.ScanSeverities, ", "))))
+	}
+
+	if scan.Mode != "" && scan.Mode != manifest.ScanModeFail && scan.Mode != manifest.ScanModeWarn {
+		errs = append(errs, NewErrInvalidField("scan.mode", fmt.Sprintf("must be '%s' or '%s'", manifest.ScanModeFail, manifest.ScanModeWarn)))
+	}
+
+	if docker.IgnoreVulnerabilities && scan.Mode == manifest.ScanModeFail {
+		errs = append(errs, NewErrInvalidField("ignore_vulnerabilities", "cannot be used together with scan.mode 'fail'"))
+	}
+
+	if scan.Report != "" && !slices.Contains(manifest.ScanReportFormats, scan.Report) {
+		errs = append(errs, NewErrInvalidField("scan.report", fmt.Sprintf("must be one of %s", strings.Join(manifest.ScanReportFormats, ", "))))
+	}
+
+	return errs
+}
+
+func lintSupplyChain(docker manifest.DockerPush, platform manifest.Platform) (errs []error) {
+	if docker.SBOM != "" && !slices.Contains(manifest.SBOMFormats, docker.SBOM) {
+		errs = append(errs, NewErrInvalidField("sbom", fmt.Sprintf("must be one of %s", strings.Join(manifest.SBOMFormats, ", "))))
+	}
+
+	isSecret := func(value string) bool {
+		return strings.HasPrefix(value, "((") && strings.HasSuffix(value, "))")
+	}
+
+	if docker.SigningKey != "" && !isSecret(docker.SigningKey) {
+		errs = append(errs, NewErrInvalidField("signing_key", "must be a secret, e.g. '((cosign.private_key))'"))
+	}
+
+	if docker.SigningKeyPassword != "" {
+		if docker.SigningKey == "" {
+			errs = append(errs, NewErrInvalidField("signing_key_password", "cannot be used without signing_key"))
+		} else if !isSecret(docker.SigningKeyPassword) {
+			errs = append(errs, NewErrInvalidField("signing_key_password", "must be a secret, e.g. '((cosign.password))'"))
+		}
+	}
+
+	if docker.SigningKey != "" && !docker.SignsImage() {
+		errs = append(errs, NewErrInvalidField("signing_key", "is only used when sign is true or sbom is set").AsWarning())
+	}
+
+	// keyless signing needs the OIDC token of GitHub Actions
+	if platform.IsConcourse() && docker.SignsImage() && docker.SigningKey == "" {
+		errs = append(errs, NewErrInvalidField("signing_key", "must be set to sign the image or attach an sbom on concourse, keyless signing is only supported on actions"))
+	}
+
+	return errs
+}
+
+var dockerTagPlaceholder = regexp.MustCompile(`\{[^}]*}`)
+
+// dockerTag is the format of a tag in the OCI distribution spec
+var dockerTag = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+func LintDockerPushTags(docker manifest.DockerPush, man manifest.Manifest) (errs []error) {
+	// the placeholders are replaced with the longest values they can have
+	replacer := strings.NewReplacer(
+		"{version}", "2.1000000.0",
+		"{major}", "2",
+		"{minor}", "2.1000000",
+		"{gitref}", strings.Repeat("0", 40),
+		"{branch}", shared.TagSafe(man.Triggers.GetGitTrigger().Branch),
+	)
+
+	var seen []string
+	for _, tag := range docker.Tags {
+		if slices.Contains(seen, tag) {
+			errs = append(errs, NewErrInvalidField("tags", fmt.Sprintf("'%s' is listed more than once", tag)))
+			continue
+		}
+		seen = append(seen, tag)
+
+		for _, placeholder := range dockerTagPlaceholder.FindAllString(tag, -1) {
+			if !slices.Contains(manifest.DockerTagPlaceholders, placeholder) {
+				errs = append(errs, NewErrInvalidField("tags", fmt.Sprintf("'%s' is not a placeholder, must be one of %s", placeholder, strings.Join(manifest.DockerTagPlaceholders, ", "))))
+			}
+		}
+
+		if !dockerTag.MatchString(replacer.Replace(tag)) {
+			errs = append(errs, NewErrInvalidField("tags", fmt.Sprintf("'%s' is not a valid tag, it must be at most 128 of the characters a-z, A-Z, 0-9, '_', '.' and '-' and must not start with '.' or '-'", tag)))
+		}
+
+		usesVersion := strings.Contains(tag, "{version}") || strings.Contains(tag, "{major}") || strings.Contains(tag, "{minor}")
+		if man.Platform.IsConcourse() && usesVersion && !man.FeatureToggles.UpdatePipeline() && !man.Triggers.HasTagTrigger() {
+			errs = append(errs, NewErrInvalidField("tags", fmt.Sprintf("'%s' uses the version, which is only set on concourse with the feature toggle '%s' or a tag trigger", tag, manifest.FeatureUpdatePipeline)))
 		}
 	}
 
@@ -3,6 +3,9 @@ package linters
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/springernature/halfpipe/config"
 )
 
 var (
@@ -41,24 +44,25 @@ var (
 	ErrCFLabelProductIsMissing      = newError("CF manifest is missing 'product' label. If 'product' is set on the CF space you can safely ignore this warning.").AsWarning()
 	ErrCFLabelEnvironmentIsMissing  = newError("CF manifest is missing 'environment' label. If 'environment' is set on the CF space you can safely ignore this warning.").AsWarning()
 
-	ErrUnsupportedRegistry = newError("image must be from halfpipe registry. Please see <https://ee.public.springernature.app/rel-eng/docker-registry/>")
-	ErrDockerPushTag       = newError("the field 'tag' is no longer used and is safe to delete")
+	ErrUnsupportedRegistry = newError("image must be from one of the registries of the organisation (HALFPIPE_DOCKER_REGISTRIES). Please see <https://ee.public.springernature.app/rel-eng/docker-registry/>")
+	ErrDockerPushTag       = newError("the field 'tag' is no longer used and is safe to delete, use 'tags' to configure the tags of the image")
 
 	ErrDockerPlatformUnknown = newError("only linux/amd64 and/or linux/arm64 are supported")
 	ErrDockerComposeVersion  = newError("the docker-compose file version used is deprecated. All services must be under the 'services' key and 'Version' must be '2' or higher. Please see <https://docs.docker.com/compose/compose-file/compose-versioning/#versioning>")
 	ErrDockerVarSecret       = newError("using a secret in docker build vars is not secure. See the 'secrets' option of the docker-push task")
 
-	ErrMultipleTriggers = newError("cannot have multiple triggers of this type")
+	ErrMultipleTriggers              = newError("cannot have multiple triggers of this type")
+	ErrUnsupportedPullRequestTrigger = newError("pull_request triggers are only supported in GitHub Actions")
+	ErrTagTriggerManualGitTrigger    = newError("the tag trigger has no effect as the git trigger has 'manual_trigger' set")
 
 	ErrVelaVariableMissing = newError("vela manifest variable is not specified in halfpipe manifest")
 	ErrVelaNamespace       = newError("vela namespace must start with 'katee-'")
 
-	ErrUnsupportedManualTrigger   = newError("manual_trigger on individual tasks is not supported in GitHub Actions. It is supported at the workflow level in git trigger options")
-	ErrUnsupportedRolling         = newError("cf rolling deploys are not supported in GitHub Actions")
-	ErrDockerTriggerLoop          = newError("cannot push docker image that is also a trigger as it will create a loop")
-	ErrUnsupportedGitPrivateKey   = newError("git private_key is not supported in GitHub Actions")
-	ErrUnsupportedGitUri          = newError("git uri is not supported in GitHub Actions")
-	ErrUnsupportedPipelineTrigger = newError("pipeline triggers are not supported in GitHub Actions")
+	ErrManualTriggerEnvironment = newError("manual_trigger in GitHub Actions waits for the reviewers of the environment with the name of the task, but GitHub creates missing environments without reviewers. Run 'halfpipe environments' to list the environments to add reviewers to")
+	ErrDockerTriggerLoop        = newError("cannot push docker image that is also a trigger as it will create a loop")
+	ErrUnsupportedGitPrivateKey = newError("git private_key is not supported in GitHub Actions")
+	ErrUnsupportedGitUri        = newError("git uri is not supported in GitHub Actions")
+	ErrUnsupportedRunnerLabel   = newError(fmt.Sprintf("runs_on must only contain the runner labels %s", strings.Join(config.ActionsRunnerLabels, ", ")))
 
 	ErrSlackSuccessMessageFieldDeprecated = newError("'slack_success_message' is deprecated, please use new notification structure")
 	ErrSlackFailureMessageFieldDeprecated = newError("'slack_failure_message' is deprecated, please use new notification structure")
@@ -69,6 +73,7 @@ type Error struct {
 	err   error
 	value string
 	level string
+	path  string
 }
 
 func newError(msg string) Error {
@@ -83,12 +88,25 @@ func (e Error) WithValue(value string) Error {
 	return Error{err: e, level: e.level, value: ": " + value}
 }
 
+// errorAt prefixes the message of err with the path of the task, trigger or image it was found in, e.g. "tasks[1].pre_promote[0]"
+func errorAt(path string, err error) Error {
+	level := "error"
+	var lintError Error
+	if errors.As(err, &lintError) {
+		level = lintError.level
+	}
+	return Error{err: err, level: level, path: path}
+}
+
 func (e Error) Error() string {
+	if e.path != "" {
+		return fmt.Sprintf("%s %s%s", e.path, e.err.Error(), e.value)
+	}
 	return fmt.Sprintf("%s%s", e.err.Error(), e.value)
 }
 
 func (e Error) AsWarning() Error {
-	return Error{err: e.err, level: "warning", value: e.value}
+	return Error{err: e.err, level: "warning", value: e.value, path: e.path}
 }
 
 func (e Error) IsWarning() bool {
@@ -99,6 +117,37 @@ func (e Error) Unwrap() error {
 	return e.err
 }
 
+// Field returns the manifest field an ErrMissingField, ErrInvalidField or ErrDeprecatedField was created for
+func (e Error) Field() string {
+	inner, ok := e.err.(Error)
+	if !ok {
+		return ""
+	}
+	if field := inner.Field(); field != "" {
+		return field
+	}
+	if inner == ErrMissingField || inner == ErrInvalidField || inner == ErrDeprecatedField {
+		return strings.TrimPrefix(e.value, ": ")
+	}
+	return ""
+}
+
+// Path returns the path in the manifest of the task or trigger the error was found in, e.g. "tasks[2].images[0]"
+func (e Error) Path() string {
+	var inner Error
+	if !errors.As(e.err, &inner) {
+		return e.path
+	}
+	switch innerPath := inner.Path(); {
+	case e.path == "":
+		return innerPath
+	case innerPath == "":
+		return e.path
+	default:
+		return e.path + "." + innerPath
+	}
+}
+
 func (e Error) Is(target error) bool {
 	t, ok := target.(Error)
 	if !ok {
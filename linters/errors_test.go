@@ -32,3 +32,23 @@ func Test_ErrorIsWarning(t *testing.T) {
 	assert.True(t, baseErr.AsWarning().IsWarning())
 	assert.True(t, baseErr.AsWarning().WithValue("v").WithFile("f").IsWarning())
 }
+
+func Test_ErrorField(t *testing.T) {
+	assert.Equal(t, "script", NewErrMissingField("script").Field())
+	assert.Equal(t, "docker.image", NewErrInvalidField("docker.image", "must be set").Field())
+	assert.Equal(t, "on_failure", NewDeprecatedField("on_failure", "use failure").AsWarning().Field())
+	assert.Equal(t, "timeout", NewErrInvalidField("timeout", "blah").WithFile("build.sh").Field())
+	assert.Equal(t, "", ErrFileNotFound.WithFile("build.sh").Field())
+}
+
+func Test_ErrorPath(t *testing.T) {
+	err := errorAt("tasks[2]", errorAt("images[0]", NewErrInvalidField("image", "must be set").AsWarning()))
+
+	assert.EqualError(t, err, "tasks[2] images[0] invalid field: image: must be set")
+	assert.Equal(t, "tasks[2].images[0]", err.Path())
+	assert.Equal(t, "image", err.Field())
+	assert.True(t, err.IsWarning())
+	assert.ErrorIs(t, err, ErrInvalidField)
+
+	assert.Equal(t, "", NewErrMissingField("script").Path())
+}
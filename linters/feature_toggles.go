@@ -9,6 +9,7 @@ var (
 	ErrUnsupportedFeature          = newError("unsupported feature")
 	ErrUnsupportedFeatureVersioned = newError("feature 'versioned' is no longer supported. The same functionality is included in the 'update-pipeline' feature")
 	ErrUnsupportedDockerDecompose  = newError("feature 'docker-decompose' is no longer supported. docker-compose tasks will not be modified")
+	ErrCompositeActionsConcourse   = newError("feature 'composite-actions' only has an effect in GitHub Actions")
 )
 
 type featureToggleLinter struct {
@@ -36,6 +37,10 @@ func (f featureToggleLinter) Lint(manifest manifest.Manifest) (result LintResult
 			}
 		}
 	}
+
+	if manifest.Platform.IsConcourse() && manifest.FeatureToggles.CompositeActions() {
+		result.Add(ErrCompositeActionsConcourse.AsWarning())
+	}
 	return result
 }
 
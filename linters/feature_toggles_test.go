@@ -43,3 +43,16 @@ func TestDoesNothingIfAllFeaturesAreAvailable(t *testing.T) {
 	assert.False(t, result.HasErrors())
 
 }
+
+func TestWarningIfCompositeActionsOnConcourse(t *testing.T) {
+	man := manifest.Manifest{
+		FeatureToggles: manifest.FeatureToggles{manifest.FeatureCompositeActions},
+	}
+
+	result := NewFeatureToggleLinter(manifest.AvailableFeatureToggles).Lint(man)
+	assert.ErrorIs(t, result.Issues[0], ErrCompositeActionsConcourse)
+
+	man.Platform = "actions"
+	result = NewFeatureToggleLinter(manifest.AvailableFeatureToggles).Lint(man)
+	assert.Empty(t, result.Issues)
+}
@@ -0,0 +1,111 @@
+package linters
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/springernature/halfpipe/manifest"
+)
+
+var outputKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+// anyOutputReference matches everything that looks like an output reference, also the ones that are not valid
+var anyOutputReference = regexp.MustCompile(`\$\{\{\s*outputs\..*?}}`)
+
+// LintOutputs checks the outputs a task declares and that the outputs it refers to in its vars are declared by a previous task
+func LintOutputs(currentTask manifest.Task, previousTasks []manifest.Task) (errs []error) {
+	outputs := manifest.TaskOutputs(currentTask)
+
+	var keys []string
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !outputKeyRegex.MatchString(key) {
+			errs = append(errs, NewErrInvalidField("outputs", fmt.Sprintf("'%s' must only contain letters, numbers, '-' and '_'", key)))
+		}
+		if outputs[key] == "" {
+			errs = append(errs, NewErrInvalidField("outputs", fmt.Sprintf("'%s' must be a path to a file", key)))
+		}
+	}
+
+	previousTaskOutputs := make(map[string]map[string]string)
+	for _, task := range manifest.TaskList(previousTasks).Flatten() {
+		previousTaskOutputs[manifest.TaskID(task.GetName())] = manifest.TaskOutputs(task)
+	}
+
+	for _, ref := range manifest.TaskOutputReferences(currentTask) {
+		taskOutputs, found := previousTaskOutputs[ref.Task]
+		if !found {
+			errs = append(errs, NewErrInvalidField("vars", fmt.Sprintf("refers to '%s' which is not a previous task", ref.Task)))
+		} else if _, found := taskOutputs[ref.Key]; !found {
+			errs = append(errs, NewErrInvalidField("vars", fmt.Sprintf("task '%s' has no output '%s'", ref.Task, ref.Key)))
+		}
+	}
+
+	errs = append(errs, lintInvalidOutputReferences(currentTask)...)
+	errs = append(errs, lintUnsupportedOutputReferences(currentTask)...)
+
+	return errs
+}
+
+// lintInvalidOutputReferences checks that everything that looks like an output reference is one, otherwise it would be
+// passed on as it is
+func lintInvalidOutputReferences(task manifest.Task) (errs []error) {
+	var vars manifest.Vars
+	switch task := task.(type) {
+	case manifest.Run:
+		vars = task.Vars
+	case manifest.DockerCompose:
+		vars = task.Vars
+	}
+
+	var keys []string
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, ref := range anyOutputReference.FindAllString(vars[key], -1) {
+			if len(manifest.OutputReferences(ref)) > 0 {
+				continue
+			}
+			errs = append(errs, NewErrInvalidField("vars", fmt.Sprintf("'%s' refers to '%s' which is not a valid output reference, refer to the task by its name in lower case with every character other than a-z, 0-9, '-' and '_' replaced with '_'", key, ref)))
+		}
+	}
+	return errs
+}
+
+// lintUnsupportedOutputReferences checks that tasks other than run and docker-compose do not refer to outputs,
+// as the references would be passed on as they are
+func lintUnsupportedOutputReferences(task manifest.Task) (errs []error) {
+	lintVars := func(field string, vars manifest.Vars) {
+		var keys []string
+		for key, value := range vars {
+			if anyOutputReference.MatchString(value) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			errs = append(errs, NewErrInvalidField(field, fmt.Sprintf("'%s' refers to an output, which is only supported in run and docker-compose tasks", key)))
+		}
+	}
+
+	switch task := task.(type) {
+	case manifest.DeployCF:
+		lintVars("vars", task.Vars)
+	case manifest.DockerPush:
+		lintVars("vars", task.Vars)
+		lintVars("secrets", task.Secrets)
+	case manifest.DeployKatee:
+		lintVars("vars", task.Vars)
+	case manifest.ConsumerIntegrationTest:
+		lintVars("vars", task.Vars)
+	}
+	return errs
+}
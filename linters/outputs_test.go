@@ -0,0 +1,91 @@
+package linters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputs(t *testing.T) {
+	build := manifest.Run{Name: "Build App", Outputs: map[string]string{"version": "target/version"}}
+
+	t.Run("valid outputs and references", func(t *testing.T) {
+		task := manifest.Run{
+			Vars:    manifest.Vars{"VERSION": "v${{ outputs.build_app.version }}"},
+			Outputs: map[string]string{"digest_1": "digest"},
+		}
+		assert.Empty(t, LintOutputs(task, []manifest.Task{build}))
+	})
+
+	t.Run("invalid outputs", func(t *testing.T) {
+		task := manifest.DockerCompose{Outputs: map[string]string{"in valid": "file", "empty": ""}}
+		errs := LintOutputs(task, nil)
+		assert.Len(t, errs, 2)
+		assertContainsError(t, errs, ErrInvalidField.WithValue("outputs"))
+	})
+
+	t.Run("references to unknown tasks and keys", func(t *testing.T) {
+		task := manifest.Run{Vars: manifest.Vars{
+			"A": "${{ outputs.deploy.version }}",
+			"B": "${{ outputs.build_app.digest }}",
+		}}
+		errs := LintOutputs(task, []manifest.Task{build})
+		assert.Len(t, errs, 2)
+		assertContainsError(t, errs, ErrInvalidField.WithValue("vars"))
+	})
+
+	t.Run("invalid references", func(t *testing.T) {
+		task := manifest.Run{Vars: manifest.Vars{
+			"A": "${{ outputs.Build App.digest }}",
+			"B": "${{ outputs.build_app.version }}-${{ outputs.build_app }}",
+		}}
+		errs := LintOutputs(task, []manifest.Task{build})
+		assert.Len(t, errs, 2)
+		assertContainsError(t, errs, ErrInvalidField.WithValue("vars"))
+		assert.Contains(t, errs[0].Error(), "'${{ outputs.Build App.digest }}'")
+		assert.Contains(t, errs[1].Error(), "'${{ outputs.build_app }}'")
+	})
+
+	t.Run("references in tasks that do not support them", func(t *testing.T) {
+		ref := "${{ outputs.build_app.version }}"
+		for _, task := range []manifest.Task{
+			manifest.DeployCF{Vars: manifest.Vars{"VERSION": ref}},
+			manifest.DockerPush{Vars: manifest.Vars{"VERSION": ref}},
+			manifest.DeployKatee{Vars: manifest.Vars{"VERSION": ref}},
+		} {
+			errs := LintOutputs(task, []manifest.Task{build})
+			assert.Len(t, errs, 1)
+			assertContainsError(t, errs, ErrInvalidField.WithValue("vars"))
+		}
+
+		errs := LintOutputs(manifest.DockerPush{Secrets: manifest.Vars{"TOKEN": ref}}, []manifest.Task{build})
+		assertContainsError(t, errs, ErrInvalidField.WithValue("secrets"))
+
+		errs = LintOutputs(manifest.DeployCF{Vars: manifest.Vars{"VERSION": "${{ outputs.Build App.version }}"}}, []manifest.Task{build})
+		assert.Len(t, errs, 1)
+		assertContainsError(t, errs, ErrInvalidField.WithValue("vars"))
+	})
+
+	t.Run("outputs of tasks in the same parallel", func(t *testing.T) {
+		man := manifest.Manifest{Tasks: manifest.TaskList{
+			manifest.Parallel{Tasks: manifest.TaskList{
+				build,
+				manifest.Run{Name: "test", Vars: manifest.Vars{"VERSION": "${{ outputs.build_app.version }}"}},
+			}},
+			manifest.Run{Name: "deploy", Vars: manifest.Vars{"VERSION": "${{ outputs.build_app.version }}"}},
+		}}
+
+		errs := NewTasksLinter(afero.Afero{Fs: afero.NewMemMapFs()}, "/repo", "linux").lintTasks("", man.Tasks, man, nil, true, false)
+		var varsErrs []string
+		for _, err := range errs {
+			if strings.Contains(err.Error(), "vars") {
+				varsErrs = append(varsErrs, err.Error())
+			}
+		}
+		assert.Len(t, varsErrs, 1)
+		assert.Contains(t, varsErrs[0], "tasks[0][1]")
+	})
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"github.com/springernature/halfpipe/manifest"
 	"golang.org/x/exp/slices"
+	"regexp"
 	"strings"
 )
 
+var githubRepository = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
 func LintPipelineTrigger(man manifest.Manifest, pipeline manifest.PipelineTrigger) (errs []error) {
 	if man.Team != pipeline.Team {
 		errs = append(errs, NewErrInvalidField("team", fmt.Sprintf("you can only trigger on pipelines in your team, '%s'!", man.Team)))
@@ -18,12 +21,26 @@ func LintPipelineTrigger(man manifest.Manifest, pipeline manifest.PipelineTrigge
 		return errs
 	}
 
-	if pipeline.Job == "" {
+	if pipeline.Job == "" && man.Platform.IsConcourse() {
 		errs = append(errs, NewErrInvalidField("job", "must not be empty"))
 		return errs
 	}
 
+	if man.Platform.IsActions() {
+		if pipeline.Repository != "" && !githubRepository.MatchString(pipeline.Repository) {
+			errs = append(errs, NewErrInvalidField("repository", "must be a GitHub repository as 'owner/name'"))
+		}
+		otherRepo := pipeline.Repository != "" && pipeline.Repository != man.Triggers.GetGitTrigger().GetRepoFullName()
+		if otherRepo && pipeline.Job == "" {
+			errs = append(errs, NewErrInvalidField("job", "must not be empty for pipelines in another repository, whole workflows can only be triggered on in the same repository"))
+		}
+	}
+
 	allowedStatus := []string{"succeeded", "failed", "errored", "aborted"}
+	if man.Platform.IsActions() {
+		// GitHub Actions only has successful and failed jobs, cancelled runs do not trigger
+		allowedStatus = []string{"succeeded", "failed"}
+	}
 	if !slices.Contains(allowedStatus, pipeline.Status) {
 		errs = append(errs, NewErrInvalidField("status", fmt.Sprintf("must be one of %s", strings.Join(allowedStatus, ", "))))
 	}
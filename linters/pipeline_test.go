@@ -76,3 +76,39 @@ func TestBadStatus(t *testing.T) {
 	errs := LintPipelineTrigger(man, trigger)
 	assertContainsError(t, errs, ErrInvalidField.WithValue("status"))
 }
+
+func TestActionsPipelineTrigger(t *testing.T) {
+	man := manifest.Manifest{Team: "team", Platform: "actions"}
+
+	t.Run("job is optional", func(t *testing.T) {
+		errs := LintPipelineTrigger(man, manifest.PipelineTrigger{Team: "team", Pipeline: "asd", Status: "succeeded"})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("only succeeded and failed", func(t *testing.T) {
+		errs := LintPipelineTrigger(man, manifest.PipelineTrigger{Team: "team", Pipeline: "asd", Job: "asdf", Status: "failed"})
+		assert.Empty(t, errs)
+
+		errs = LintPipelineTrigger(man, manifest.PipelineTrigger{Team: "team", Pipeline: "asd", Job: "asdf", Status: "aborted"})
+		assertContainsError(t, errs, ErrInvalidField.WithValue("status"))
+	})
+
+	t.Run("job must be set for pipelines in another repository", func(t *testing.T) {
+		man := man
+		man.Triggers = manifest.TriggerList{manifest.GitTrigger{URI: "git@github.com:springernature/halfpipe.git"}}
+
+		errs := LintPipelineTrigger(man, manifest.PipelineTrigger{Team: "team", Pipeline: "asd", Status: "succeeded", Repository: "springernature/halfpipe"})
+		assert.Empty(t, errs)
+
+		errs = LintPipelineTrigger(man, manifest.PipelineTrigger{Team: "team", Pipeline: "asd", Status: "succeeded", Repository: "springernature/other"})
+		assertContainsError(t, errs, ErrInvalidField.WithValue("job"))
+
+		errs = LintPipelineTrigger(man, manifest.PipelineTrigger{Team: "team", Pipeline: "asd", Job: "asdf", Status: "succeeded", Repository: "springernature/other"})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("repository must be owner/name", func(t *testing.T) {
+		errs := LintPipelineTrigger(man, manifest.PipelineTrigger{Team: "team", Pipeline: "asd", Job: "asdf", Status: "succeeded", Repository: "https://github.com/springernature/other"})
+		assertContainsError(t, errs, ErrInvalidField.WithValue("repository"))
+	})
+}
@@ -15,6 +15,12 @@ func LintPrePromoteTask(task manifest.Task) (errs []error) {
 		if task.GetNotifications().NotificationsDefined() {
 			errs = append(errs, NewErrInvalidField("notifications", "you are not allowed to configure notifications inside a pre promote task"))
 		}
+		if len(manifest.TaskOutputs(task)) > 0 || len(manifest.TaskOutputReferences(task)) > 0 {
+			errs = append(errs, NewErrInvalidField("outputs", "you are not allowed to use outputs inside a pre promote task"))
+		}
+		if len(task.GetWatchedPaths()) > 0 {
+			errs = append(errs, NewErrInvalidField("watched_paths", "you are not allowed to have watched paths inside a pre promote task"))
+		}
 	default:
 		errs = append(errs, NewErrInvalidField("type", "you are only allowed to use 'run', 'consumer-integration-test' or 'docker-compose' tasks as pre promotes"))
 	}
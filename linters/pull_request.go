@@ -0,0 +1,30 @@
+package linters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/springernature/halfpipe/manifest"
+	"golang.org/x/exp/slices"
+)
+
+// pullRequestTypes are the activity types of the pull_request event in GitHub Actions
+var pullRequestTypes = []string{
+	"assigned", "unassigned", "labeled", "unlabeled", "opened", "edited", "closed", "reopened", "synchronize",
+	"converted_to_draft", "ready_for_review", "locked", "unlocked", "review_requested", "review_request_removed",
+	"auto_merge_enabled", "auto_merge_disabled",
+}
+
+func LintPullRequestTrigger(pullRequest manifest.PullRequestTrigger, platform manifest.Platform) (errs []error) {
+	if !platform.IsActions() {
+		errs = append(errs, ErrUnsupportedPullRequestTrigger)
+		return errs
+	}
+
+	for _, t := range pullRequest.Types {
+		if !slices.Contains(pullRequestTypes, t) {
+			errs = append(errs, NewErrInvalidField("types", fmt.Sprintf("'%s' must be one of %s", t, strings.Join(pullRequestTypes, ", "))))
+		}
+	}
+	return errs
+}
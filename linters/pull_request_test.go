@@ -0,0 +1,30 @@
+package linters
+
+import (
+	"testing"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequestTrigger(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		trigger := manifest.PullRequestTrigger{Types: []string{"opened", "synchronize", "ready_for_review"}}
+
+		errs := LintPullRequestTrigger(trigger, "actions")
+		assert.Empty(t, errs)
+	})
+
+	t.Run("only supported in actions", func(t *testing.T) {
+		errs := LintPullRequestTrigger(manifest.PullRequestTrigger{}, "concourse")
+		assertContainsError(t, errs, ErrUnsupportedPullRequestTrigger)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		trigger := manifest.PullRequestTrigger{Types: []string{"opened", "merged"}}
+
+		errs := LintPullRequestTrigger(trigger, "actions")
+		assert.Len(t, errs, 1)
+		assertContainsError(t, errs, ErrInvalidField.WithValue("types"))
+	})
+}
@@ -0,0 +1,236 @@
+package linters
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/springernature/halfpipe/config"
+	"github.com/springernature/halfpipe/manifest"
+)
+
+// ReportIssue is a single lint issue in a format that is easy to consume by other tools
+type ReportIssue struct {
+	Linter   string `json:"linter"`
+	DocsURL  string `json:"docsUrl"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Path     string `json:"path,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// Report flattens the lint results into a list of issues.
+// basePath is the path from the root of the repo to the halfpipe manifest so that files are relative to the repo.
+func (lrs LintResults) Report(basePath string) (issues []ReportIssue) {
+	issues = []ReportIssue{}
+	for _, lr := range lrs {
+		for _, err := range deduplicate(lr.Issues) {
+			issue := ReportIssue{
+				Linter:   lr.Linter,
+				DocsURL:  lr.DocsURL,
+				Severity: "error",
+				Message:  err.Error(),
+			}
+
+			if isWarning(err) {
+				issue.Severity = "warning"
+			}
+
+			var lintError Error
+			if errors.As(err, &lintError) {
+				issue.Path = lintError.Path()
+			}
+
+			var located manifest.LocatedError
+			if errors.As(err, &located) {
+				issue.Message = located.Err.Error()
+				if issue.Path == "" && located.Path != "" {
+					issue.Path = located.Path
+				}
+				if located.Position.File != "" {
+					issue.File = path.Join(basePath, located.Position.File)
+				}
+				issue.Line = located.Position.Line
+				issue.Column = located.Position.Column
+			}
+
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func (lrs LintResults) JSON(basePath string) ([]byte, error) {
+	return json.MarshalIndent(lrs.Report(basePath), "", "  ")
+}
+
+// ManifestLintResults are the lint results of one of the manifests in a repo with several pipelines
+type ManifestLintResults struct {
+	// BasePath is the path from the root of the repo to the directory of the manifest
+	BasePath string
+	// ManifestFile is the path from the root of the repo to the manifest
+	ManifestFile string
+	LintResults  LintResults
+}
+
+// RepoLintResults aggregates the lint results of all manifests in a repo into a single report
+type RepoLintResults []ManifestLintResults
+
+func (rlrs RepoLintResults) HasErrors() bool {
+	return slices.ContainsFunc(rlrs, func(mlr ManifestLintResults) bool { return mlr.LintResults.HasErrors() })
+}
+
+func (rlrs RepoLintResults) Report() (issues []ReportIssue) {
+	issues = []ReportIssue{}
+	for _, mlr := range rlrs {
+		issues = append(issues, mlr.LintResults.Report(mlr.BasePath)...)
+	}
+	return issues
+}
+
+func (rlrs RepoLintResults) JSON() ([]byte, error) {
+	return json.MarshalIndent(rlrs.Report(), "", "  ")
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	HelpURI string       `json:"helpUri,omitempty"`
+	Short   sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// SARIF renders the lint results as a SARIF 2.1.0 log that can be uploaded to code scanning.
+// Issues without a position are reported against manifestFile, the path of the halfpipe manifest in the repo.
+func (lrs LintResults) SARIF(basePath string, manifestFile string) ([]byte, error) {
+	return RepoLintResults{{BasePath: basePath, ManifestFile: manifestFile, LintResults: lrs}}.SARIF()
+}
+
+// SARIF renders the lint results of all manifests as a single SARIF 2.1.0 log
+func (rlrs RepoLintResults) SARIF() ([]byte, error) {
+	driver := sarifDriver{
+		Name:           "halfpipe",
+		Version:        config.Version,
+		InformationURI: "https://ee.public.springernature.app/rel-eng/halfpipe/",
+		Rules:          []sarifRule{},
+	}
+
+	ruleIndex := map[string]int{}
+	for _, lr := range rlrs.lintResults() {
+		if _, found := ruleIndex[lr.Linter]; !found {
+			ruleIndex[lr.Linter] = len(driver.Rules)
+			driver.Rules = append(driver.Rules, sarifRule{
+				ID:      lr.Linter,
+				Name:    lr.Linter,
+				HelpURI: lr.DocsURL,
+				Short:   sarifMessage{Text: lr.Linter + " linter"},
+			})
+		}
+	}
+
+	results := []sarifResult{}
+	for _, mlr := range rlrs {
+		for _, issue := range mlr.LintResults.Report(mlr.BasePath) {
+			results = append(results, sarifResultFor(issue, ruleIndex[issue.Linter], mlr.ManifestFile))
+		}
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+}
+
+func (rlrs RepoLintResults) lintResults() (lrs LintResults) {
+	for _, mlr := range rlrs {
+		lrs = append(lrs, mlr.LintResults...)
+	}
+	return lrs
+}
+
+func sarifResultFor(issue ReportIssue, ruleIndex int, manifestFile string) sarifResult {
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: manifestFile, URIBaseID: "%SRCROOT%"},
+		},
+	}
+	if issue.File != "" {
+		location.PhysicalLocation.ArtifactLocation.URI = issue.File
+	}
+	if issue.Line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+	}
+	if issue.Path != "" {
+		location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: issue.Path}}
+	}
+
+	return sarifResult{
+		RuleID:    issue.Linter,
+		RuleIndex: ruleIndex,
+		Level:     issue.Severity,
+		Message:   sarifMessage{Text: issue.Message},
+		Locations: []sarifLocation{location},
+	}
+}
@@ -0,0 +1,91 @@
+package linters
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func reportLintResults() LintResults {
+	_, sourceMap, _ := manifest.ParseWithSourceMap(".halfpipe.io", `team: My team
+pipeline: my pipeline
+tasks:
+- type: run
+  script: ./build.sh
+`)
+
+	return LintResults{
+		NewLintResult("Top", "top-url", []error{
+			NewErrInvalidField("team", "should be lower case").AsWarning(),
+		}),
+		NewLintResult("Tasks", "tasks-url", []error{
+			errorAt("tasks[0]", ErrFileNotFound.WithFile("./build.sh")),
+			errors.New("somewhere"),
+		}),
+	}.WithSourceMap(sourceMap)
+}
+
+func TestReport(t *testing.T) {
+	assert.Equal(t, []ReportIssue{
+		{Linter: "Top", DocsURL: "top-url", Severity: "warning", Message: "invalid field: team: should be lower case", Path: "team", File: "sub/.halfpipe.io", Line: 1, Column: 1},
+		{Linter: "Tasks", DocsURL: "tasks-url", Severity: "error", Message: "tasks[0] file not found (./build.sh)", Path: "tasks[0]", File: "sub/.halfpipe.io", Line: 4, Column: 3},
+		{Linter: "Tasks", DocsURL: "tasks-url", Severity: "error", Message: "somewhere"},
+	}, reportLintResults().Report("sub"))
+
+	assert.Equal(t, []ReportIssue{}, LintResults{}.Report(""))
+}
+
+func TestSARIF(t *testing.T) {
+	out, err := reportLintResults().SARIF("sub", "sub/.halfpipe.io")
+	assert.NoError(t, err)
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(out, &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, []string{"Top", "Tasks"}, []string{run.Tool.Driver.Rules[0].ID, run.Tool.Driver.Rules[1].ID})
+	assert.Len(t, run.Results, 3)
+
+	assert.Equal(t, "warning", run.Results[0].Level)
+	assert.Equal(t, 0, run.Results[0].RuleIndex)
+	assert.Equal(t, "sub/.halfpipe.io", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, &sarifRegion{StartLine: 1, StartColumn: 1}, run.Results[0].Locations[0].PhysicalLocation.Region)
+
+	assert.Equal(t, "error", run.Results[1].Level)
+	assert.Equal(t, 1, run.Results[1].RuleIndex)
+	assert.Equal(t, "tasks[0]", run.Results[1].Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	assert.Equal(t, "sub/.halfpipe.io", run.Results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, run.Results[2].Locations[0].PhysicalLocation.Region)
+}
+
+func TestRepoLintResults(t *testing.T) {
+	repoLintResults := RepoLintResults{
+		{BasePath: "a", ManifestFile: "a/.halfpipe.io", LintResults: reportLintResults()},
+		{BasePath: "b", ManifestFile: "b/.halfpipe.io", LintResults: LintResults{NewLintResult("Other", "other-url", []error{errors.New("no position")})}},
+	}
+
+	assert.True(t, repoLintResults.HasErrors())
+
+	issues := repoLintResults.Report()
+	assert.Len(t, issues, 4)
+	assert.Equal(t, "a/.halfpipe.io", issues[0].File)
+	assert.Equal(t, "Other", issues[3].Linter)
+
+	out, err := repoLintResults.SARIF()
+	assert.NoError(t, err)
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(out, &log))
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 3)
+	assert.Len(t, run.Results, 4)
+	assert.Equal(t, 2, run.Results[3].RuleIndex)
+	assert.Equal(t, "b/.halfpipe.io", run.Results[3].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
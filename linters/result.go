@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gookit/color"
+	"github.com/springernature/halfpipe/manifest"
 	"golang.org/x/exp/slices"
+	"strings"
 )
 
 type LintResults []LintResult
@@ -27,6 +29,44 @@ func (lrs LintResults) Error() (out string) {
 	return out
 }
 
+// WithSourceMap annotates all issues with their position in the manifest.
+// The node is found from the path of the task or trigger the issue was found in, e.g. "tasks[1].pre_promote[0]",
+// together with the field the issue is about.
+func (lrs LintResults) WithSourceMap(sourceMap manifest.SourceMap) (updated LintResults) {
+	for _, lr := range lrs {
+		var issues []error
+		for _, issue := range lr.Issues {
+			issues = append(issues, locateIssue(sourceMap, issue))
+		}
+		lr.Issues = issues
+		updated = append(updated, lr)
+	}
+	return updated
+}
+
+func locateIssue(sourceMap manifest.SourceMap, issue error) error {
+	var lintError Error
+	if !errors.As(issue, &lintError) {
+		return issue
+	}
+
+	path := lintError.Path()
+	if lintError.Field() != "" {
+		withField := lintError.Field()
+		if path != "" {
+			withField = path + "." + withField
+		}
+		if _, found := sourceMap[strings.ReplaceAll(withField, "][", "].tasks[")]; found {
+			path = withField
+		}
+	}
+
+	if path == "" {
+		return issue
+	}
+	return sourceMap.Locate(path, issue)
+}
+
 type LintResult struct {
 	Linter  string
 	DocsURL string
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	"github.com/springernature/halfpipe/manifest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,3 +73,37 @@ func TestDeduplicateErrorsAndWarnings(t *testing.T) {
 	assert.Equal(t, 1, strings.Count(lintResult.Error(), warn2.Error()))
 
 }
+
+func TestWithSourceMap(t *testing.T) {
+	_, sourceMap, _ := manifest.ParseWithSourceMap(".halfpipe.io", `team: my team
+pipeline: my pipeline
+tasks:
+- type: run
+  script: ./build.sh
+- type: parallel
+  tasks:
+  - type: run
+    timeout: blah
+`)
+
+	unknown := errors.New("not in the manifest")
+	lintResults := LintResults{
+		NewLintResult("linter", "url", []error{
+			NewErrInvalidField("team", "should be lower case").AsWarning(),
+			NewErrMissingField("slack_channel"),
+			errorAt("tasks[0]", ErrFileNotFound.WithFile("./build.sh")),
+			errorAt("tasks[1][0]", NewErrInvalidField("timeout", "invalid duration")),
+			unknown,
+		}),
+	}.WithSourceMap(sourceMap)
+
+	issues := lintResults[0].Issues
+	assert.EqualError(t, issues[0], ".halfpipe.io:1:1: invalid field: team: should be lower case")
+	assert.EqualError(t, issues[1], "required field missing: slack_channel")
+	assert.EqualError(t, issues[2], ".halfpipe.io:4:3: tasks[0] file not found (./build.sh)")
+	assert.EqualError(t, issues[3], ".halfpipe.io:9:5: tasks[1][0] invalid field: timeout: invalid duration")
+	assert.Equal(t, unknown, issues[4])
+
+	assert.True(t, lintResults.HasWarnings())
+	assert.True(t, lintResults.HasErrors())
+}
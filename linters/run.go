@@ -33,7 +33,7 @@ func LintRunTask(run manifest.Run, fs afero.Afero, os string) (errs []error) {
 		errs = append(errs, NewErrInvalidField("retries", "must be between 0 and 5"))
 	}
 
-	if run.Docker.Image == "" {
+	if run.Docker.Image == "" && run.Container.Image == "" {
 		errs = append(errs, NewErrMissingField("docker.image"))
 	}
 
@@ -46,3 +46,24 @@ func LintRunTask(run manifest.Run, fs afero.Afero, os string) (errs []error) {
 
 	return errs
 }
+
+// LintRunContainer lints the container the job of a run task runs in, which is only possible in GitHub Actions
+func LintRunContainer(run manifest.Run, platform manifest.Platform) (errs []error) {
+	if run.Container.Image == "" {
+		return errs
+	}
+
+	if !platform.IsActions() {
+		return append(errs, NewErrInvalidField("container", "only supported in GitHub Actions, use 'docker' instead"))
+	}
+
+	if run.Docker.Image != "" {
+		errs = append(errs, NewErrInvalidField("container", "cannot be used together with 'docker'"))
+	}
+
+	// the job container is started before any step, so the credentials cannot be fetched from vault
+	if strings.Contains(run.Container.Username, "((") || strings.Contains(run.Container.Password, "((") {
+		errs = append(errs, NewErrInvalidField("container", "credentials cannot be vault secrets, use GitHub secrets instead"))
+	}
+	return errs
+}
@@ -182,3 +182,26 @@ func TestShouldSkipExecutableTestAndProduceWarningIfRunningOnWindows(t *testing.
 	errs := LintRunTask(task, fs, "windows")
 	assertContainsError(t, errs, ErrWindowsScriptMustBeExecutable.WithFile("build.sh"))
 }
+
+func TestRunTaskContainer(t *testing.T) {
+	t.Run("does not need docker image", func(t *testing.T) {
+		errors := LintRunTask(manifest.Run{Container: manifest.Docker{Image: "alpine"}}, afero.Afero{}, "")
+		assertNotContainsError(t, errors, NewErrMissingField("docker.image"))
+	})
+
+	t.Run("only supported in actions", func(t *testing.T) {
+		task := manifest.Run{Container: manifest.Docker{Image: "alpine"}}
+		assertContainsError(t, LintRunContainer(task, "concourse"), ErrInvalidField.WithValue("container"))
+		assert.Empty(t, LintRunContainer(task, "actions"))
+	})
+
+	t.Run("cannot be combined with docker", func(t *testing.T) {
+		task := manifest.Run{Container: manifest.Docker{Image: "alpine"}, Docker: manifest.Docker{Image: "alpine"}}
+		assertContainsError(t, LintRunContainer(task, "actions"), ErrInvalidField.WithValue("container"))
+	})
+
+	t.Run("credentials cannot be vault secrets", func(t *testing.T) {
+		task := manifest.Run{Container: manifest.Docker{Image: "private/image", Username: "user", Password: "((docker.password))"}}
+		assertContainsError(t, LintRunContainer(task, "actions"), ErrInvalidField.WithValue("container"))
+	})
+}
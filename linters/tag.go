@@ -0,0 +1,18 @@
+package linters
+
+import (
+	"strings"
+
+	"github.com/springernature/halfpipe/manifest"
+)
+
+func LintTagTrigger(tag manifest.TagTrigger, git manifest.GitTrigger) (errs []error) {
+	if tag.Semver && (!strings.HasSuffix(tag.Pattern, "*") || strings.ContainsAny(tag.VersionPrefix(), "*?[")) {
+		errs = append(errs, NewErrInvalidField("pattern", "with 'semver' the pattern must be a prefix followed by '*', e.g. 'v*'"))
+	}
+
+	if git.ManualTrigger {
+		errs = append(errs, ErrTagTriggerManualGitTrigger.AsWarning())
+	}
+	return errs
+}
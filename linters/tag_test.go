@@ -0,0 +1,29 @@
+package linters
+
+import (
+	"testing"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagTrigger(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.Empty(t, LintTagTrigger(manifest.TagTrigger{Pattern: "release-*"}, manifest.GitTrigger{}))
+		assert.Empty(t, LintTagTrigger(manifest.TagTrigger{Pattern: "v*", Semver: true}, manifest.GitTrigger{}))
+		assert.Empty(t, LintTagTrigger(manifest.TagTrigger{Pattern: "*", Semver: true}, manifest.GitTrigger{}))
+	})
+
+	t.Run("semver needs a prefix pattern", func(t *testing.T) {
+		errs := LintTagTrigger(manifest.TagTrigger{Pattern: "v*-lib", Semver: true}, manifest.GitTrigger{})
+		assertContainsError(t, errs, ErrInvalidField.WithValue("pattern"))
+
+		errs = LintTagTrigger(manifest.TagTrigger{Pattern: "*/v*", Semver: true}, manifest.GitTrigger{})
+		assertContainsError(t, errs, ErrInvalidField.WithValue("pattern"))
+	})
+
+	t.Run("git trigger with manual_trigger", func(t *testing.T) {
+		errs := LintTagTrigger(manifest.TagTrigger{Pattern: "*"}, manifest.GitTrigger{ManualTrigger: true})
+		assertContainsError(t, errs, ErrTagTriggerManualGitTrigger)
+	})
+}
@@ -1,10 +1,8 @@
 package linters
 
 import (
-	"code.cloudfoundry.org/cli/util/manifestparser"
 	"fmt"
 	"github.com/spf13/afero"
-	"github.com/springernature/halfpipe/cf"
 	"github.com/springernature/halfpipe/manifest"
 	"sort"
 	"strings"
@@ -12,40 +10,30 @@ import (
 )
 
 type taskLinter struct {
-	Fs                              afero.Afero
-	lintRunTask                     func(task manifest.Run, fs afero.Afero, os string) []error
-	lintDeployCFTask                func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) []error
-	lintDeployKateeTask             func(task manifest.DeployKatee, man manifest.Manifest, fs afero.Afero) []error
-	LintPrePromoteTask              func(task manifest.Task) []error
-	lintDockerPushTask              func(task manifest.DockerPush, fs afero.Afero) []error
-	lintDockerComposeTask           func(task manifest.DockerCompose, fs afero.Afero) []error
-	lintConsumerIntegrationTestTask func(task manifest.ConsumerIntegrationTest, providerHostRequired bool) []error
-	lintDeployMLZipTask             func(task manifest.DeployMLZip) []error
-	lintDeployMLModulesTask         func(task manifest.DeployMLModules) []error
-	lintArtifacts                   func(currentTask manifest.Task, previousTasks []manifest.Task) []error
-	lintParallel                    func(parallelTask manifest.Parallel) []error
-	lintSequence                    func(seqTask manifest.Sequence, cameFromAParallel bool) []error
-	lintNotifications               func(task manifest.Task) []error
-	os                              string
+	Fs                 afero.Afero
+	workingDir         string
+	taskLinters        map[string]TaskLintFunc
+	LintPrePromoteTask func(task manifest.Task) []error
+	lintArtifacts      func(currentTask manifest.Task, previousTasks []manifest.Task) []error
+	lintOutputs        func(currentTask manifest.Task, previousTasks []manifest.Task) []error
+	lintParallel       func(parallelTask manifest.Parallel) []error
+	lintSequence       func(seqTask manifest.Sequence, cameFromAParallel bool) []error
+	lintNotifications  func(task manifest.Task) []error
+	os                 string
 }
 
-func NewTasksLinter(fs afero.Afero, os string) taskLinter {
+func NewTasksLinter(fs afero.Afero, workingDir string, os string) taskLinter {
 	return taskLinter{
-		Fs:                              fs,
-		lintRunTask:                     LintRunTask,
-		lintDeployCFTask:                LintDeployCFTask,
-		lintDeployKateeTask:             LintDeployKateeTask,
-		LintPrePromoteTask:              LintPrePromoteTask,
-		lintDockerPushTask:              LintDockerPushTask,
-		lintDockerComposeTask:           LintDockerComposeTask,
-		lintConsumerIntegrationTestTask: LintConsumerIntegrationTestTask,
-		lintDeployMLZipTask:             LintDeployMLZipTask,
-		lintDeployMLModulesTask:         LintDeployMLModulesTask,
-		lintArtifacts:                   LintArtifacts,
-		lintParallel:                    LintParallelTask,
-		lintSequence:                    LintSequenceTask,
-		lintNotifications:               LintNotifications,
-		os:                              os,
+		Fs:                 fs,
+		workingDir:         workingDir,
+		taskLinters:        taskLinters,
+		LintPrePromoteTask: LintPrePromoteTask,
+		lintArtifacts:      LintArtifacts,
+		lintOutputs:        LintOutputs,
+		lintParallel:       LintParallelTask,
+		lintSequence:       LintSequenceTask,
+		lintNotifications:  LintNotifications,
+		os:                 os,
 	}
 }
 
@@ -65,6 +53,9 @@ func (linter taskLinter) Lint(man manifest.Manifest) (result LintResult) {
 }
 
 func (linter taskLinter) lintTasks(listName string, ts []manifest.Task, man manifest.Manifest, previousTasks []manifest.Task, lintArtifact, cameFromParallel bool) (rE []error) {
+	// tasks in the same parallel run at the same time, so they cannot use the outputs of each other
+	previousTasksOfParallel := previousTasks
+
 	for index, t := range ts {
 		previousTasks = append(previousTasks, ts[:index]...)
 
@@ -81,10 +72,9 @@ func (linter taskLinter) lintTasks(listName string, ts []manifest.Task, man mani
 
 		var errs []error
 		switch task := t.(type) {
-		case manifest.Run:
-			errs = linter.lintRunTask(task, linter.Fs, linter.os)
 		case manifest.DeployCF:
-			errs = linter.lintDeployCFTask(task, manifestparser.ManifestParser{}.InterpolateAndParse, linter.Fs)
+			errs = linter.lintTask(task, listName, man)
+			errs = append(errs, LintWatchedPaths(task, man, linter.Fs, linter.workingDir)...)
 
 			if len(errs) == 0 && len(task.PrePromote) > 0 {
 				for pI, preTask := range task.PrePromote {
@@ -96,22 +86,6 @@ func (linter taskLinter) lintTasks(listName string, ts []manifest.Task, man mani
 				subErrors := linter.lintTasks(fmt.Sprintf("%s.pre_promote", taskID), task.PrePromote, man, previousTasks, false, false)
 				errs = append(errs, subErrors...)
 			}
-		case manifest.DeployKatee:
-			errs = linter.lintDeployKateeTask(task, man, linter.Fs)
-		case manifest.DockerPush:
-			errs = linter.lintDockerPushTask(task, linter.Fs)
-		case manifest.DockerCompose:
-			errs = linter.lintDockerComposeTask(task, linter.Fs)
-		case manifest.ConsumerIntegrationTest:
-			if listName == "tasks" {
-				errs = linter.lintConsumerIntegrationTestTask(task, true)
-			} else {
-				errs = linter.lintConsumerIntegrationTestTask(task, false)
-			}
-		case manifest.DeployMLZip:
-			errs = linter.lintDeployMLZipTask(task)
-		case manifest.DeployMLModules:
-			errs = linter.lintDeployMLModulesTask(task)
 		case manifest.Update:
 		case manifest.Parallel:
 			errs = linter.lintParallel(task)
@@ -126,7 +100,12 @@ func (linter taskLinter) lintTasks(listName string, ts []manifest.Task, man mani
 			lintTimeout = false
 			lintArtifact = false
 		default:
-			errs = append(errs, NewErrInvalidField("task", fmt.Sprintf("%s is not a known task", taskID)))
+			if manifest.TaskType(t) == "" {
+				errs = append(errs, NewErrInvalidField("task", fmt.Sprintf("%s is not a known task", taskID)))
+			} else {
+				errs = linter.lintTask(t, listName, man)
+				errs = append(errs, LintWatchedPaths(t, man, linter.Fs, linter.workingDir)...)
+			}
 		}
 
 		if t.ReadsFromArtifacts() && lintArtifact {
@@ -134,6 +113,12 @@ func (linter taskLinter) lintTasks(listName string, ts []manifest.Task, man mani
 			errs = append(errs, artifactErr...)
 		}
 
+		if cameFromParallel {
+			errs = append(errs, linter.lintOutputs(t, previousTasksOfParallel)...)
+		} else {
+			errs = append(errs, linter.lintOutputs(t, previousTasks)...)
+		}
+
 		if lintTimeout && t.GetTimeout() != "" {
 			_, err := time.ParseDuration(t.GetTimeout())
 			if err != nil {
@@ -167,7 +152,7 @@ func wrapErrorsWithIndex(prefix string) func(errs []error) (rE []error) {
 			if strings.HasPrefix(e.Error(), prefix) {
 				rE = append(rE, e)
 			} else {
-				rE = append(rE, fmt.Errorf("%s %w", prefix, e))
+				rE = append(rE, errorAt(prefix, e))
 			}
 
 		}
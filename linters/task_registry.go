@@ -0,0 +1,66 @@
+package linters
+
+import (
+	"code.cloudfoundry.org/cli/util/manifestparser"
+	"github.com/spf13/afero"
+	"github.com/springernature/halfpipe/manifest"
+)
+
+// TaskLintContext is everything a task linter gets to lint a task
+type TaskLintContext struct {
+	Fs       afero.Afero
+	OS       string
+	Manifest manifest.Manifest
+	// ListName is the path of the list the task is in, "" for the top level tasks
+	ListName string
+}
+
+// TaskLintFunc lints a task of the type it is registered for
+type TaskLintFunc func(task manifest.Task, ctx TaskLintContext) []error
+
+var taskLinters = map[string]TaskLintFunc{}
+
+// RegisterTaskLinter sets the linter for a type of task registered with manifest.RegisterTask.
+// The timeout, notifications and artifacts of the task are linted for all types of task.
+func RegisterTaskLinter(taskType string, linter TaskLintFunc) {
+	taskLinters[taskType] = linter
+}
+
+func (linter taskLinter) lintTask(task manifest.Task, listName string, man manifest.Manifest) []error {
+	lint, found := linter.taskLinters[manifest.TaskType(task)]
+	if !found {
+		return nil
+	}
+	return lint(task, TaskLintContext{Fs: linter.Fs, OS: linter.os, Manifest: man, ListName: listName})
+}
+
+func init() {
+	RegisterTaskLinter("run", func(task manifest.Task, ctx TaskLintContext) []error {
+		run := task.(manifest.Run)
+		errs := append(LintRunTask(run, ctx.Fs, ctx.OS), LintRunContainer(run, ctx.Manifest.Platform)...)
+		return append(errs, LintCache(run.Cache, ctx.Manifest.Triggers.GetGitTrigger().BasePath)...)
+	})
+	RegisterTaskLinter("deploy-cf", func(task manifest.Task, ctx TaskLintContext) []error {
+		return LintDeployCFTask(task.(manifest.DeployCF), manifestparser.ManifestParser{}.InterpolateAndParse, ctx.Fs)
+	})
+	RegisterTaskLinter("deploy-katee", func(task manifest.Task, ctx TaskLintContext) []error {
+		return LintDeployKateeTask(task.(manifest.DeployKatee), ctx.Manifest, ctx.Fs)
+	})
+	RegisterTaskLinter("docker-push", func(task manifest.Task, ctx TaskLintContext) []error {
+		dockerPush := task.(manifest.DockerPush)
+		return append(LintDockerPushTask(dockerPush, ctx.Manifest.Platform, ctx.Fs), LintDockerPushTags(dockerPush, ctx.Manifest)...)
+	})
+	RegisterTaskLinter("docker-compose", func(task manifest.Task, ctx TaskLintContext) []error {
+		dockerCompose := task.(manifest.DockerCompose)
+		return append(LintDockerComposeTask(dockerCompose, ctx.Fs), LintCache(dockerCompose.Cache, ctx.Manifest.Triggers.GetGitTrigger().BasePath)...)
+	})
+	RegisterTaskLinter("consumer-integration-test", func(task manifest.Task, ctx TaskLintContext) []error {
+		return LintConsumerIntegrationTestTask(task.(manifest.ConsumerIntegrationTest), ctx.ListName == "tasks")
+	})
+	RegisterTaskLinter("deploy-ml-zip", func(task manifest.Task, ctx TaskLintContext) []error {
+		return LintDeployMLZipTask(task.(manifest.DeployMLZip))
+	})
+	RegisterTaskLinter("deploy-ml-modules", func(task manifest.Task, ctx TaskLintContext) []error {
+		return LintDeployMLModulesTask(task.(manifest.DeployMLModules))
+	})
+}
@@ -2,7 +2,6 @@ package linters
 
 import (
 	"fmt"
-	"github.com/springernature/halfpipe/cf"
 	"github.com/stretchr/testify/assert"
 	"testing"
 
@@ -101,46 +100,48 @@ func TestCallsOutToTheLintersCorrectly(t *testing.T) {
 		Fs: afero.Afero{
 			Fs: nil,
 		},
-		lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-			calledLintRunTask = true
-			calledLintRunTaskNum++
-			return
-		},
-		lintDeployCFTask: func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) (errs []error) {
-			calledLintDeployCFTask = true
-			calledLintDeployCFTaskNum++
-			return
+		taskLinters: map[string]TaskLintFunc{
+			"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				calledLintRunTask = true
+				calledLintRunTaskNum++
+				return
+			},
+			"deploy-cf": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				calledLintDeployCFTask = true
+				calledLintDeployCFTaskNum++
+				return
+			},
+			"docker-push": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				calledLintDockerPushTask = true
+				calledLintDockerPushTaskNum++
+				return
+			},
+			"docker-compose": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				calledLintDockerComposeTask = true
+				calledLintDockerComposeTaskNum++
+				return
+			},
+			"consumer-integration-test": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				calledLintConsumerIntegrationTestTask = true
+				calledLintConsumerIntegrationTestTaskNum++
+				return
+			},
+			"deploy-ml-zip": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				calledLintDeployMLZipTask = true
+				calledLintDeployMLZipTaskNum++
+				return
+			},
+			"deploy-ml-modules": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				calledLintDeployMLModulesTask = true
+				calledLintDeployMLModulesTaskNum++
+				return
+			},
 		},
 		LintPrePromoteTask: func(tasks manifest.Task) (errs []error) {
 			calledLintPrePromoteTasks = true
 			calledLintPrePromoteTasksNum++
 			return
 		},
-		lintDockerPushTask: func(task manifest.DockerPush, fs afero.Afero) (errs []error) {
-			calledLintDockerPushTask = true
-			calledLintDockerPushTaskNum++
-			return
-		},
-		lintDockerComposeTask: func(task manifest.DockerCompose, fs afero.Afero) (errs []error) {
-			calledLintDockerComposeTask = true
-			calledLintDockerComposeTaskNum++
-			return
-		},
-		lintConsumerIntegrationTestTask: func(cit manifest.ConsumerIntegrationTest, providerHostRequired bool) (errs []error) {
-			calledLintConsumerIntegrationTestTask = true
-			calledLintConsumerIntegrationTestTaskNum++
-			return
-		},
-		lintDeployMLZipTask: func(task manifest.DeployMLZip) (errs []error) {
-			calledLintDeployMLZipTask = true
-			calledLintDeployMLZipTaskNum++
-			return
-		},
-		lintDeployMLModulesTask: func(task manifest.DeployMLModules) (errs []error) {
-			calledLintDeployMLModulesTask = true
-			calledLintDeployMLModulesTaskNum++
-			return
-		},
 		lintArtifacts: func(currentTask manifest.Task, previousTasks []manifest.Task) (errs []error) {
 			return
 		},
@@ -155,6 +156,7 @@ func TestCallsOutToTheLintersCorrectly(t *testing.T) {
 			wasCalledFromParallelTask = append(wasCalledFromParallelTask, cameFromAParallel)
 			return
 		},
+		lintOutputs: LintOutputs,
 		lintNotifications: func(task manifest.Task) (errs []error) {
 			calledLintNotifications = true
 			calledLintNotificationsNum++
@@ -234,28 +236,31 @@ func TestMergesTheErrorsAndWarningsCorrectlyWithPrePromote(t *testing.T) {
 		Fs: afero.Afero{
 			Fs: nil,
 		},
-		lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-			return []error{runErr1, runErr2, runWarn1}
-		},
-		lintDeployCFTask: func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) (errs []error) {
-			return
+		taskLinters: map[string]TaskLintFunc{
+			"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{runErr1, runErr2, runWarn1}
+			},
+			"deploy-cf": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return
+			},
+			"docker-push": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{dockerPushErr, dockerPushWarn}
+			},
+			"deploy-ml-zip": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{deployMlZipErr}
+			},
+			"deploy-ml-modules": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{deployMlModulesWarn}
+
+			},
 		},
 		LintPrePromoteTask: func(tasks manifest.Task) (errs []error) {
 			return []error{prePromoteErr, prePromoteWarn}
 		},
-		lintDockerPushTask: func(task manifest.DockerPush, fs afero.Afero) (errs []error) {
-			return []error{dockerPushErr, dockerPushWarn}
-		},
-		lintDeployMLZipTask: func(task manifest.DeployMLZip) (errs []error) {
-			return []error{deployMlZipErr}
-		},
-		lintDeployMLModulesTask: func(task manifest.DeployMLModules) (errs []error) {
-			return []error{deployMlModulesWarn}
-
-		},
 		lintArtifacts: func(currentTask manifest.Task, previousTasks []manifest.Task) (errs []error) {
 			return
 		},
+		lintOutputs:       LintOutputs,
 		lintNotifications: func(task manifest.Task) (errs []error) { return },
 	}
 
@@ -326,31 +331,34 @@ func TestMergesTheErrorsAndWarningsCorrectlyWithParallel(t *testing.T) {
 		Fs: afero.Afero{
 			Fs: nil,
 		},
-		lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-			return []error{runErr1, runErr2, runWarn1}
-		},
-		lintDeployCFTask: func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) (errs []error) {
-			return
+		taskLinters: map[string]TaskLintFunc{
+			"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{runErr1, runErr2, runWarn1}
+			},
+			"deploy-cf": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return
+			},
+			"docker-push": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{dockerPushErr, dockerPushWarn}
+			},
+			"deploy-ml-zip": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{deployMlZipErr}
+			},
+			"deploy-ml-modules": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return []error{deployMlModulesWarn}
+
+			},
 		},
 		LintPrePromoteTask: func(tasks manifest.Task) (errs []error) {
 			return []error{prePromoteErr, prePromoteWarn}
 		},
-		lintDockerPushTask: func(task manifest.DockerPush, fs afero.Afero) (errs []error) {
-			return []error{dockerPushErr, dockerPushWarn}
-		},
-		lintDeployMLZipTask: func(task manifest.DeployMLZip) (errs []error) {
-			return []error{deployMlZipErr}
-		},
-		lintDeployMLModulesTask: func(task manifest.DeployMLModules) (errs []error) {
-			return []error{deployMlModulesWarn}
-
-		},
 		lintArtifacts: func(currentTask manifest.Task, previousTasks []manifest.Task) (errs []error) {
 			return
 		},
 		lintParallel: func(parallelTask manifest.Parallel) (errs []error) {
 			return
 		},
+		lintOutputs:       LintOutputs,
 		lintNotifications: func(task manifest.Task) (errs []error) { return },
 	}
 
@@ -386,12 +394,15 @@ func TestLintArtifactsWithParallelSeq(t *testing.T) {
 
 	t.Run("no previous steps saves artifacts", func(t *testing.T) {
 		taskLinter := taskLinter{Fs: afero.Afero{},
-			lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-				return
+			taskLinters: map[string]TaskLintFunc{
+				"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
 			},
 			lintParallel:      func(parallelTask manifest.Parallel) (errs []error) { return },
 			lintSequence:      func(seqTask manifest.Sequence, cameFromAParallel bool) (errs []error) { return },
 			lintArtifacts:     LintArtifacts,
+			lintOutputs:       LintOutputs,
 			lintNotifications: func(task manifest.Task) (errs []error) { return },
 		}
 
@@ -419,12 +430,15 @@ func TestLintArtifactsWithParallelSeq(t *testing.T) {
 
 	t.Run("a previous steps saves artifacts", func(t *testing.T) {
 		taskLinter := taskLinter{Fs: afero.Afero{},
-			lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-				return
+			taskLinters: map[string]TaskLintFunc{
+				"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
 			},
 			lintParallel:      func(parallelTask manifest.Parallel) (errs []error) { return },
 			lintSequence:      func(seqTask manifest.Sequence, cameFromAParallel bool) (errs []error) { return },
 			lintArtifacts:     LintArtifacts,
+			lintOutputs:       LintOutputs,
 			lintNotifications: func(task manifest.Task) (errs []error) { return },
 		}
 
@@ -451,12 +465,15 @@ func TestLintArtifactsWithParallelSeq(t *testing.T) {
 
 	t.Run("a previous steps in the sequence saves artifacts", func(t *testing.T) {
 		taskLinter := taskLinter{Fs: afero.Afero{},
-			lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-				return
+			taskLinters: map[string]TaskLintFunc{
+				"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
 			},
 			lintParallel:      func(parallelTask manifest.Parallel) (errs []error) { return },
 			lintSequence:      func(seqTask manifest.Sequence, cameFromAParallel bool) (errs []error) { return },
 			lintArtifacts:     LintArtifacts,
+			lintOutputs:       LintOutputs,
 			lintNotifications: func(task manifest.Task) (errs []error) { return },
 		}
 
@@ -486,14 +503,17 @@ func TestLintArtifactsWithPrePromote(t *testing.T) {
 
 	t.Run("A previous non pre promote step have saved artifact", func(t *testing.T) {
 		taskLinter := taskLinter{Fs: afero.Afero{},
-			lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-				return
-			},
-			lintDeployCFTask: func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) (errs []error) {
-				return
+			taskLinters: map[string]TaskLintFunc{
+				"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
+				"deploy-cf": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
 			},
 			LintPrePromoteTask: func(tasks manifest.Task) (errs []error) { return },
 			lintArtifacts:      LintArtifacts,
+			lintOutputs:        LintOutputs,
 			lintNotifications:  func(task manifest.Task) (errs []error) { return },
 		}
 		man := manifest.Manifest{
@@ -518,14 +538,17 @@ func TestLintArtifactsWithPrePromote(t *testing.T) {
 
 	t.Run("A previous step haven't saved artifacts and the deploy uses a generated manifest manifest path", func(t *testing.T) {
 		taskLinter := taskLinter{Fs: afero.Afero{},
-			lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-				return
-			},
-			lintDeployCFTask: func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) (errs []error) {
-				return
+			taskLinters: map[string]TaskLintFunc{
+				"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
+				"deploy-cf": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
 			},
 			LintPrePromoteTask: func(tasks manifest.Task) (errs []error) { return },
 			lintArtifacts:      LintArtifacts,
+			lintOutputs:        LintOutputs,
 			lintNotifications:  func(task manifest.Task) (errs []error) { return },
 		}
 		man := manifest.Manifest{
@@ -543,14 +566,17 @@ func TestLintArtifactsWithPrePromote(t *testing.T) {
 
 	t.Run("A previous step have saved artifacts and the deploy uses a generated manifest manifest path", func(t *testing.T) {
 		taskLinter := taskLinter{Fs: afero.Afero{},
-			lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-				return
-			},
-			lintDeployCFTask: func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) (errs []error) {
-				return
+			taskLinters: map[string]TaskLintFunc{
+				"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
+				"deploy-cf": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+					return
+				},
 			},
 			LintPrePromoteTask: func(tasks manifest.Task) (errs []error) { return },
 			lintArtifacts:      LintArtifacts,
+			lintOutputs:        LintOutputs,
 			lintNotifications:  func(task manifest.Task) (errs []error) { return },
 		}
 		man := manifest.Manifest{
@@ -569,27 +595,30 @@ func TestLintArtifactsWithPrePromote(t *testing.T) {
 
 func TestLintTimeout(t *testing.T) {
 	taskLinter := taskLinter{
-		lintRunTask: func(task manifest.Run, fs afero.Afero, os string) (errs []error) {
-			return
-		},
-		lintDeployCFTask: func(task manifest.DeployCF, readCfManifest cf.ManifestReader, fs afero.Afero) (errs []error) {
-			return
+		taskLinters: map[string]TaskLintFunc{
+			"run": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return
+			},
+			"deploy-cf": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return
+			},
+			"docker-push": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return
+			},
+			"docker-compose": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return
+			},
+			"consumer-integration-test": func(task manifest.Task, ctx TaskLintContext) (errs []error) {
+				return
+			},
+			"deploy-ml-modules": func(task manifest.Task, ctx TaskLintContext) (errs []error) { return },
+			"deploy-ml-zip":     func(task manifest.Task, ctx TaskLintContext) (errs []error) { return },
 		},
 		LintPrePromoteTask: func(task manifest.Task) (errs []error) { return },
-		lintDockerPushTask: func(task manifest.DockerPush, fs afero.Afero) (errs []error) {
-			return
-		},
-		lintDockerComposeTask: func(task manifest.DockerCompose, fs afero.Afero) (errs []error) {
-			return
-		},
-		lintConsumerIntegrationTestTask: func(task manifest.ConsumerIntegrationTest, providerHostRequired bool) (errs []error) {
-			return
-		},
-		lintDeployMLZipTask:     func(task manifest.DeployMLZip) (errs []error) { return },
-		lintDeployMLModulesTask: func(task manifest.DeployMLModules) (errs []error) { return },
 		lintArtifacts: func(currentTask manifest.Task, previousTasks []manifest.Task) (errs []error) {
 			return
 		},
+		lintOutputs:       LintOutputs,
 		lintNotifications: func(task manifest.Task) (errs []error) { return },
 	}
 
@@ -16,11 +16,15 @@ type triggersLinter struct {
 	cronLinter      func(cron manifest.TimerTrigger) []error
 	dockerLinter    func(docker manifest.DockerTrigger) []error
 	pipelineLinter  func(man manifest.Manifest, pipeline manifest.PipelineTrigger) []error
+	prLinter        func(pullRequest manifest.PullRequestTrigger, platform manifest.Platform) []error
+	tagLinter       func(tag manifest.TagTrigger, git manifest.GitTrigger) []error
 }
 
 func (t triggersLinter) lintOnlyOneOfEach(triggers manifest.TriggerList) (errs []error) {
 	numGit := 0
 	numTimer := 0
+	numPullRequest := 0
+	numTag := 0
 
 	for _, trigger := range triggers {
 		switch trigger.(type) {
@@ -28,6 +32,10 @@ func (t triggersLinter) lintOnlyOneOfEach(triggers manifest.TriggerList) (errs [
 			numGit++
 		case manifest.TimerTrigger:
 			numTimer++
+		case manifest.PullRequestTrigger:
+			numPullRequest++
+		case manifest.TagTrigger:
+			numTag++
 		}
 	}
 
@@ -39,6 +47,14 @@ func (t triggersLinter) lintOnlyOneOfEach(triggers manifest.TriggerList) (errs [
 		errs = append(errs, ErrMultipleTriggers.WithValue("timer"))
 	}
 
+	if numPullRequest > 1 {
+		errs = append(errs, ErrMultipleTriggers.WithValue("pull_request"))
+	}
+
+	if numTag > 1 {
+		errs = append(errs, ErrMultipleTriggers.WithValue("tag"))
+	}
+
 	return errs
 }
 
@@ -57,6 +73,10 @@ func (t triggersLinter) lintTrigger(man manifest.Manifest) (errs []error) {
 			e = t.dockerLinter(trigger)
 		case manifest.PipelineTrigger:
 			e = t.pipelineLinter(man, trigger)
+		case manifest.PullRequestTrigger:
+			e = t.prLinter(trigger, man.Platform)
+		case manifest.TagTrigger:
+			e = t.tagLinter(trigger, man.Triggers.GetGitTrigger())
 		}
 
 		errs = append(errs, wrapWithIndex(e)...)
@@ -89,5 +109,7 @@ func NewTriggersLinter(fs afero.Afero, workingDir string, branchResolver project
 		cronLinter:      LintCronTrigger,
 		dockerLinter:    LintDockerTrigger,
 		pipelineLinter:  LintPipelineTrigger,
+		prLinter:        LintPullRequestTrigger,
+		tagLinter:       LintTagTrigger,
 	}
 }
@@ -178,3 +178,17 @@ func TestReturnsErrorsCorrectlyAndWithIndexedPrefix(t *testing.T) {
 	assert.Equal(t, result.Issues[2].Error(), errors.New("triggers[2] dockerError").Error())
 	assert.Equal(t, result.Issues[3].Error(), errors.New("triggers[3] pipelineError").Error())
 }
+
+func TestOnlyOnePullRequestTriggerAllowed(t *testing.T) {
+	linter := NewTriggersLinter(afero.Afero{}, "", nil, nil)
+
+	result := linter.Lint(manifest.Manifest{
+		Platform: "actions",
+		Triggers: manifest.TriggerList{
+			manifest.PullRequestTrigger{},
+			manifest.PullRequestTrigger{},
+		},
+	})
+	assert.Len(t, result.Issues, 1)
+	assertContainsError(t, result.Issues, ErrMultipleTriggers)
+}
@@ -0,0 +1,27 @@
+package linters
+
+import (
+	"github.com/spf13/afero"
+	"github.com/springernature/halfpipe/manifest"
+)
+
+// LintWatchedPaths checks that the watched paths of a task match files in the repo, like the watched paths of the git trigger
+func LintWatchedPaths(task manifest.Task, man manifest.Manifest, fs afero.Afero, workingDir string) (errs []error) {
+	watchedPaths := task.GetWatchedPaths()
+	if len(watchedPaths) == 0 {
+		return nil
+	}
+
+	git := man.Triggers.GetGitTrigger()
+	for _, glob := range watchedPaths {
+		if err := checkGlob(glob, git.BasePath, workingDir, fs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if man.Platform.IsConcourse() {
+		errs = append(errs, NewErrInvalidField("watched_paths", "in Concourse the tasks after this one only run for commits this task has run for").AsWarning())
+	}
+
+	return errs
+}
@@ -0,0 +1,39 @@
+package linters
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchedPaths(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	workingDir := "/repo/services/a"
+	fs.Mkdir("/repo/services/a/src", 0777)
+
+	man := manifest.Manifest{
+		Platform: "actions",
+		Triggers: manifest.TriggerList{manifest.GitTrigger{BasePath: "services/a"}},
+	}
+
+	t.Run("no watched paths", func(t *testing.T) {
+		assert.Empty(t, LintWatchedPaths(manifest.DockerPush{}, man, fs, workingDir))
+	})
+
+	t.Run("paths relative to the repo", func(t *testing.T) {
+		task := manifest.DockerPush{WatchedPaths: []string{"services/a/src", "services/*/src", "services/b"}}
+		errs := LintWatchedPaths(task, man, fs, workingDir)
+		assert.Len(t, errs, 1)
+		assertContainsError(t, errs, ErrFileNotFound.WithValue("services/b"))
+	})
+
+	t.Run("concourse", func(t *testing.T) {
+		man := man
+		man.Platform = "concourse"
+		errs := LintWatchedPaths(manifest.Run{WatchedPaths: []string{"services/a"}}, man, fs, workingDir)
+		assert.Len(t, errs, 1)
+		assertContainsError(t, errs, ErrInvalidField.WithValue("watched_paths"))
+	})
+}
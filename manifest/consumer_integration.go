@@ -17,7 +17,10 @@ type ConsumerIntegrationTest struct {
 	Notifications        Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
 	Timeout              string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 	BuildHistory         int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	SkipOnPullRequest    bool          `json:"skip_on_pull_request,omitempty" yaml:"skip_on_pull_request,omitempty"`
 	UseCovenant          bool          `json:"use_covenant,omitempty" yaml:"use_covenant,omitempty"`
+	RunsOn               []string      `json:"runs_on,omitempty" yaml:"runs_on,omitempty"`
+	WatchedPaths         []string      `json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
 }
 
 func (r ConsumerIntegrationTest) GetSecrets() map[string]string {
@@ -83,6 +86,18 @@ func (r ConsumerIntegrationTest) IsManualTrigger() bool {
 	return false
 }
 
+func (r ConsumerIntegrationTest) SkipsOnPullRequest() bool {
+	return r.SkipOnPullRequest
+}
+
+func (r ConsumerIntegrationTest) GetRunsOn() []string {
+	return r.RunsOn
+}
+
+func (r ConsumerIntegrationTest) GetWatchedPaths() []string {
+	return r.WatchedPaths
+}
+
 func (r ConsumerIntegrationTest) SavesArtifacts() bool {
 	return false
 }
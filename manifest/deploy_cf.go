@@ -7,30 +7,33 @@ import (
 )
 
 type DeployCF struct {
-	Type            string
-	Name            string        `yaml:"name,omitempty"`
-	ManualTrigger   bool          `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
-	API             string        `yaml:"api,omitempty" secretAllowed:"true"`
-	Space           string        `yaml:"space,omitempty" secretAllowed:"true"`
-	Org             string        `yaml:"org,omitempty" secretAllowed:"true"`
-	Username        string        `yaml:"username,omitempty" secretAllowed:"true"`
-	Password        string        `yaml:"password,omitempty" secretAllowed:"true"`
-	Manifest        string        `yaml:"manifest,omitempty"`
-	TestDomain      string        `json:"test_domain" yaml:"test_domain,omitempty" secretAllowed:"true"`
-	Vars            Vars          `yaml:"vars,omitempty" secretAllowed:"true"`
-	DeployArtifact  string        `json:"deploy_artifact" yaml:"deploy_artifact,omitempty"`
-	PrePromote      TaskList      `json:"pre_promote" yaml:"pre_promote,omitempty"`
-	Timeout         string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
-	Retries         int           `yaml:"retries,omitempty"`
-	NotifyOnSuccess bool          `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
-	Notifications   Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
-	PreStart        []string      `json:"pre_start,omitempty" yaml:"pre_start,omitempty"`
-	Rolling         bool          `yaml:"rolling,omitempty"`
-	IsDockerPush    bool          `json:"-" yaml:"-"`
-	CliVersion      string        `json:"cli_version,omitempty" yaml:"cli_version,omitempty"`
-	DockerTag       string        `json:"docker_tag,omitempty" yaml:"docker_tag,omitempty"`
-	BuildHistory    int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
-	SSORoute        string        `json:"sso_route,omitempty" yaml:"sso_route,omitempty"`
+	Type              string
+	Name              string        `yaml:"name,omitempty"`
+	ManualTrigger     bool          `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
+	API               string        `yaml:"api,omitempty" secretAllowed:"true"`
+	Space             string        `yaml:"space,omitempty" secretAllowed:"true"`
+	Org               string        `yaml:"org,omitempty" secretAllowed:"true"`
+	Username          string        `yaml:"username,omitempty" secretAllowed:"true"`
+	Password          string        `yaml:"password,omitempty" secretAllowed:"true"`
+	Manifest          string        `yaml:"manifest,omitempty"`
+	TestDomain        string        `json:"test_domain" yaml:"test_domain,omitempty" secretAllowed:"true"`
+	Vars              Vars          `yaml:"vars,omitempty" secretAllowed:"true"`
+	DeployArtifact    string        `json:"deploy_artifact" yaml:"deploy_artifact,omitempty"`
+	PrePromote        TaskList      `json:"pre_promote" yaml:"pre_promote,omitempty"`
+	Timeout           string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retries           int           `yaml:"retries,omitempty"`
+	NotifyOnSuccess   bool          `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
+	Notifications     Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	PreStart          []string      `json:"pre_start,omitempty" yaml:"pre_start,omitempty"`
+	Rolling           bool          `yaml:"rolling,omitempty"`
+	IsDockerPush      bool          `json:"-" yaml:"-"`
+	CliVersion        string        `json:"cli_version,omitempty" yaml:"cli_version,omitempty"`
+	DockerTag         string        `json:"docker_tag,omitempty" yaml:"docker_tag,omitempty"`
+	BuildHistory      int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	SkipOnPullRequest *bool         `json:"skip_on_pull_request,omitempty" yaml:"skip_on_pull_request,omitempty"`
+	SSORoute          string        `json:"sso_route,omitempty" yaml:"sso_route,omitempty"`
+	RunsOn            []string      `json:"runs_on,omitempty" yaml:"runs_on,omitempty"`
+	WatchedPaths      []string      `json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
 
 	CfApplication manifestparser.Application `json:"-" yaml:"-"`
 }
@@ -98,6 +101,19 @@ func (r DeployCF) IsManualTrigger() bool {
 	return r.ManualTrigger
 }
 
+// SkipsOnPullRequest is true unless skip_on_pull_request is set to false, so pull requests do not deploy by default
+func (r DeployCF) SkipsOnPullRequest() bool {
+	return r.SkipOnPullRequest == nil || *r.SkipOnPullRequest
+}
+
+func (r DeployCF) GetRunsOn() []string {
+	return r.RunsOn
+}
+
+func (r DeployCF) GetWatchedPaths() []string {
+	return r.WatchedPaths
+}
+
 func (r DeployCF) SavesArtifacts() bool {
 	return false
 }
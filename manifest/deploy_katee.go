@@ -12,10 +12,13 @@ type DeployKatee struct {
 	Notifications          Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
 	Tag                    string        `json:"tag,omitempty" yaml:"tag,omitempty"`
 	BuildHistory           int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	SkipOnPullRequest      *bool         `json:"skip_on_pull_request,omitempty" yaml:"skip_on_pull_request,omitempty"`
 	Environment            string        `json:"environment,omitempty" yaml:"environment,omitempty"`
 	Namespace              string        `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 	DeploymentCheckTimeout int           `json:"deployment_check_timeout,omitempty" yaml:"deployment_check_timeout,omitempty"`
 	PlatformVersion        string        `json:"platform_version,omitempty" yaml:"platform_version,omitempty"`
+	RunsOn                 []string      `json:"runs_on,omitempty" yaml:"runs_on,omitempty"`
+	WatchedPaths           []string      `json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
 }
 
 func (d DeployKatee) ReadsFromArtifacts() bool {
@@ -38,6 +41,19 @@ func (d DeployKatee) IsManualTrigger() bool {
 	return d.ManualTrigger
 }
 
+// SkipsOnPullRequest is true unless skip_on_pull_request is set to false, so pull requests do not deploy by default
+func (d DeployKatee) SkipsOnPullRequest() bool {
+	return d.SkipOnPullRequest == nil || *d.SkipOnPullRequest
+}
+
+func (r DeployKatee) GetRunsOn() []string {
+	return r.RunsOn
+}
+
+func (r DeployKatee) GetWatchedPaths() []string {
+	return r.WatchedPaths
+}
+
 func (d DeployKatee) NotifiesOnSuccess() bool {
 	return d.NotifyOnSuccess
 }
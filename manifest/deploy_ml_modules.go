@@ -1,21 +1,24 @@
 package manifest
 
 type DeployMLModules struct {
-	Type             string
-	Name             string        `yaml:"name,omitempty"`
-	MLModulesVersion string        `json:"ml_modules_version" yaml:"ml_modules_version,omitempty"`
-	AppName          string        `json:"app_name" yaml:"app_name,omitempty"`
-	AppVersion       string        `json:"app_version" yaml:"app_version,omitempty"`
-	Targets          []string      `yaml:"targets,omitempty" secretAllowed:"true"`
-	ManualTrigger    bool          `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
-	Retries          int           `yaml:"retries,omitempty"`
-	NotifyOnSuccess  bool          `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
-	Notifications    Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
-	Timeout          string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
-	UseBuildVersion  bool          `json:"use_build_version,omitempty" yaml:"use_build_version,omitempty"`
-	Username         string        `json:"username" yaml:"username,omitempty" secretAllowed:"true"`
-	Password         string        `json:"password" yaml:"password,omitempty" secretAllowed:"true"`
-	BuildHistory     int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	Type              string
+	Name              string        `yaml:"name,omitempty"`
+	MLModulesVersion  string        `json:"ml_modules_version" yaml:"ml_modules_version,omitempty"`
+	AppName           string        `json:"app_name" yaml:"app_name,omitempty"`
+	AppVersion        string        `json:"app_version" yaml:"app_version,omitempty"`
+	Targets           []string      `yaml:"targets,omitempty" secretAllowed:"true"`
+	ManualTrigger     bool          `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
+	Retries           int           `yaml:"retries,omitempty"`
+	NotifyOnSuccess   bool          `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
+	Notifications     Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	Timeout           string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	UseBuildVersion   bool          `json:"use_build_version,omitempty" yaml:"use_build_version,omitempty"`
+	Username          string        `json:"username" yaml:"username,omitempty" secretAllowed:"true"`
+	Password          string        `json:"password" yaml:"password,omitempty" secretAllowed:"true"`
+	BuildHistory      int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	SkipOnPullRequest *bool         `json:"skip_on_pull_request,omitempty" yaml:"skip_on_pull_request,omitempty"`
+	RunsOn            []string      `json:"runs_on,omitempty" yaml:"runs_on,omitempty"`
+	WatchedPaths      []string      `json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
 }
 
 func (r DeployMLModules) GetSecrets() map[string]string {
@@ -82,6 +85,19 @@ func (r DeployMLModules) IsManualTrigger() bool {
 	return r.ManualTrigger
 }
 
+// SkipsOnPullRequest is true unless skip_on_pull_request is set to false, so pull requests do not deploy by default
+func (r DeployMLModules) SkipsOnPullRequest() bool {
+	return r.SkipOnPullRequest == nil || *r.SkipOnPullRequest
+}
+
+func (r DeployMLModules) GetRunsOn() []string {
+	return r.RunsOn
+}
+
+func (r DeployMLModules) GetWatchedPaths() []string {
+	return r.WatchedPaths
+}
+
 func (r DeployMLModules) SavesArtifacts() bool {
 	return false
 }
@@ -7,20 +7,25 @@ import (
 
 type DockerCompose struct {
 	Type                   string
-	Name                   string        `yaml:"name,omitempty"`
-	Command                string        `yaml:"command,omitempty"`
-	ManualTrigger          bool          `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
-	Vars                   Vars          `yaml:"vars,omitempty" secretAllowed:"true"`
-	Service                string        `yaml:"service,omitempty"`
-	ComposeFiles           ComposeFiles  `json:"compose_file" yaml:"compose_file,omitempty"`
-	SaveArtifacts          []string      `json:"save_artifacts" yaml:"save_artifacts,omitempty"`
-	RestoreArtifacts       bool          `json:"restore_artifacts" yaml:"restore_artifacts,omitempty"`
-	SaveArtifactsOnFailure []string      `json:"save_artifacts_on_failure" yaml:"save_artifacts_on_failure,omitempty"`
-	Retries                int           `yaml:"retries,omitempty"`
-	NotifyOnSuccess        bool          `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
-	Notifications          Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
-	Timeout                string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
-	BuildHistory           int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	Name                   string            `yaml:"name,omitempty"`
+	Command                string            `yaml:"command,omitempty"`
+	ManualTrigger          bool              `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
+	Vars                   Vars              `yaml:"vars,omitempty" secretAllowed:"true"`
+	Cache                  Cache             `json:"cache,omitempty" yaml:"cache,omitempty"`
+	Outputs                map[string]string `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Service                string            `yaml:"service,omitempty"`
+	ComposeFiles           ComposeFiles      `json:"compose_file" yaml:"compose_file,omitempty"`
+	SaveArtifacts          []string          `json:"save_artifacts" yaml:"save_artifacts,omitempty"`
+	RestoreArtifacts       bool              `json:"restore_artifacts" yaml:"restore_artifacts,omitempty"`
+	SaveArtifactsOnFailure []string          `json:"save_artifacts_on_failure" yaml:"save_artifacts_on_failure,omitempty"`
+	Retries                int               `yaml:"retries,omitempty"`
+	NotifyOnSuccess        bool              `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
+	Notifications          Notifications     `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	Timeout                string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	BuildHistory           int               `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	SkipOnPullRequest      bool              `json:"skip_on_pull_request,omitempty" yaml:"skip_on_pull_request,omitempty"`
+	RunsOn                 []string          `json:"runs_on,omitempty" yaml:"runs_on,omitempty"`
+	WatchedPaths           []string          `json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
 }
 
 func (r DockerCompose) GetSecrets() map[string]string {
@@ -88,6 +93,18 @@ func (r DockerCompose) IsManualTrigger() bool {
 	return r.ManualTrigger
 }
 
+func (r DockerCompose) SkipsOnPullRequest() bool {
+	return r.SkipOnPullRequest
+}
+
+func (r DockerCompose) GetRunsOn() []string {
+	return r.RunsOn
+}
+
+func (r DockerCompose) GetWatchedPaths() []string {
+	return r.WatchedPaths
+}
+
 func (r DockerCompose) SavesArtifacts() bool {
 	return len(r.SaveArtifacts) > 0
 }
@@ -9,6 +9,11 @@ type DockerPush struct {
 	Image                 string        `yaml:"image,omitempty"`
 	IgnoreVulnerabilities bool          `json:"ignore_vulnerabilities,omitempty" yaml:"ignore_vulnerabilities,omitempty"`
 	ScanTimeout           int           `json:"scan_timeout,omitempty" yaml:"scan_timeout,omitempty"`
+	Scan                  Scan          `json:"scan,omitempty" yaml:"scan,omitempty"`
+	SBOM                  string        `json:"sbom,omitempty" yaml:"sbom,omitempty"`
+	Sign                  bool          `json:"sign,omitempty" yaml:"sign,omitempty"`
+	SigningKey            string        `json:"signing_key,omitempty" yaml:"signing_key,omitempty" secretAllowed:"true"`
+	SigningKeyPassword    string        `json:"signing_key_password,omitempty" yaml:"signing_key_password,omitempty" secretAllowed:"true"`
 	Vars                  Vars          `yaml:"vars,omitempty" secretAllowed:"true"`
 	Secrets               Vars          `yaml:"secrets,omitempty" secretAllowed:"true"`
 	RestoreArtifacts      bool          `json:"restore_artifacts" yaml:"restore_artifacts,omitempty"`
@@ -18,10 +23,98 @@ type DockerPush struct {
 	Timeout               string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 	DockerfilePath        string        `json:"dockerfile_path,omitempty" yaml:"dockerfile_path,omitempty"`
 	BuildPath             string        `json:"build_path,omitempty" yaml:"build_path,omitempty"`
+	Target                string        `json:"target,omitempty" yaml:"target,omitempty"`
+	BakeFile              string        `json:"bake_file,omitempty" yaml:"bake_file,omitempty"`
+	Images                []DockerImage `json:"images,omitempty" yaml:"images,omitempty"`
 	Tag                   string        `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Tags                  []string      `json:"tags,omitempty" yaml:"tags,omitempty"`
 	BuildHistory          int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	SkipOnPullRequest     *bool         `json:"skip_on_pull_request,omitempty" yaml:"skip_on_pull_request,omitempty"`
 	Platforms             []string      `json:"platforms,omitempty" yaml:"platforms,omitempty"`
 	UseCache              bool          `json:"use_cache,omitempty" yaml:"use_cache,omitempty"`
+	RunsOn                []string      `json:"runs_on,omitempty" yaml:"runs_on,omitempty"`
+	WatchedPaths          []string      `json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
+}
+
+// DockerImage is one of the images built by a docker-push task, the dockerfile and build path default to the ones of the task
+type DockerImage struct {
+	Image          string `json:"image,omitempty" yaml:"image,omitempty"`
+	Target         string `json:"target,omitempty" yaml:"target,omitempty"`
+	DockerfilePath string `json:"dockerfile_path,omitempty" yaml:"dockerfile_path,omitempty"`
+	BuildPath      string `json:"build_path,omitempty" yaml:"build_path,omitempty"`
+}
+
+// ImageTasks returns a docker-push per image the task builds, without images it is the task itself
+func (r DockerPush) ImageTasks() []DockerPush {
+	if len(r.Images) == 0 {
+		return []DockerPush{r}
+	}
+
+	var tasks []DockerPush
+	for _, image := range r.Images {
+		task := r
+		task.Images = nil
+		task.Image = image.Image
+		task.Target = image.Target
+		if image.DockerfilePath != "" {
+			task.DockerfilePath = image.DockerfilePath
+		}
+		if image.BuildPath != "" {
+			task.BuildPath = image.BuildPath
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// Scan configures the vulnerability scan of the image, the image is only pushed when the scan passes
+type Scan struct {
+	Severity      string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	IgnoreUnfixed *bool  `json:"ignore_unfixed,omitempty" yaml:"ignore_unfixed,omitempty"`
+	Mode          string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	IgnoreFile    string `json:"ignore_file,omitempty" yaml:"ignore_file,omitempty"`
+	Report        string `json:"report,omitempty" yaml:"report,omitempty"`
+}
+
+const (
+	ScanModeFail = "fail"
+	ScanModeWarn = "warn"
+)
+
+var ScanSeverities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+var ScanReportFormats = []string{"sarif", "json"}
+
+// Severities returns the severity of the scan and all severities above it
+func (s Scan) Severities() []string {
+	for i, severity := range ScanSeverities {
+		if severity == s.Severity {
+			return ScanSeverities[i:]
+		}
+	}
+	return nil
+}
+
+// ReportFile is the file the report of the scan is written to, relative to the manifest
+func (s Scan) ReportFile() string {
+	if s.Report == "" {
+		return ""
+	}
+	return "trivy-report." + s.Report
+}
+
+// DockerTagPlaceholders are the placeholders that can be used in the tags of the image,
+// {major} and {minor} are the first one and two parts of the version
+var DockerTagPlaceholders = []string{"{version}", "{major}", "{minor}", "{gitref}", "{branch}"}
+
+// DefaultDockerTags are the tags of the image when no tags are configured
+var DefaultDockerTags = []string{"{gitref}", "{version}", "latest"}
+
+var SBOMFormats = []string{"spdx", "cyclonedx"}
+
+// SignsImage is true when the image is signed or has an SBOM attached, as the SBOM is attached as a signed attestation
+func (r DockerPush) SignsImage() bool {
+	return r.Sign || r.SBOM != ""
 }
 
 func (r DockerPush) GetSecrets() map[string]string {
@@ -88,8 +181,21 @@ func (r DockerPush) IsManualTrigger() bool {
 	return r.ManualTrigger
 }
 
+// SkipsOnPullRequest is true unless skip_on_pull_request is set to false, so pull requests do not push images by default
+func (r DockerPush) SkipsOnPullRequest() bool {
+	return r.SkipOnPullRequest == nil || *r.SkipOnPullRequest
+}
+
+func (r DockerPush) GetRunsOn() []string {
+	return r.RunsOn
+}
+
+func (r DockerPush) GetWatchedPaths() []string {
+	return r.WatchedPaths
+}
+
 func (r DockerPush) SavesArtifacts() bool {
-	return false
+	return r.Scan.Report != ""
 }
 
 func (r DockerPush) ReadsFromArtifacts() bool {
@@ -0,0 +1,26 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerPushImageTasks(t *testing.T) {
+	single := DockerPush{Name: "push", Image: "team/app", DockerfilePath: "Dockerfile"}
+	assert.Equal(t, []DockerPush{single}, single.ImageTasks())
+
+	multi := DockerPush{
+		Name:           "push",
+		DockerfilePath: "Dockerfile",
+		BuildPath:      ".",
+		Images: []DockerImage{
+			{Image: "team/app", Target: "app"},
+			{Image: "team/migrations", DockerfilePath: "db/Dockerfile", BuildPath: "db"},
+		},
+	}
+	assert.Equal(t, []DockerPush{
+		{Name: "push", Image: "team/app", Target: "app", DockerfilePath: "Dockerfile", BuildPath: "."},
+		{Name: "push", Image: "team/migrations", DockerfilePath: "db/Dockerfile", BuildPath: "db"},
+	}, multi.ImageTasks())
+}
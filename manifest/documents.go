@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"regexp"
+	"strings"
+)
+
+var documentSeparator = regexp.MustCompile(`^---(\s.*)?$`)
+
+// SplitDocuments splits a manifest with several yaml documents, one per pipeline, into the documents.
+// Every document is padded with empty lines so that line numbers in errors are the ones in the file.
+// Documents that are empty or only contain comments are dropped, a manifest with a single document is returned as is.
+func SplitDocuments(manifestYaml string) (documents []string) {
+	lines := strings.SplitAfter(manifestYaml, "\n")
+
+	start := 0
+	addDocument := func(end int) {
+		document := strings.Join(lines[start:end], "")
+		if !isEmptyDocument(document) {
+			documents = append(documents, strings.Repeat("\n", start)+document)
+		}
+	}
+
+	for i, line := range lines {
+		if documentSeparator.MatchString(strings.TrimRight(line, "\r\n")) {
+			addDocument(i)
+			start = i + 1
+		}
+	}
+	addDocument(len(lines))
+
+	if len(documents) <= 1 {
+		return []string{manifestYaml}
+	}
+	return documents
+}
+
+func isEmptyDocument(document string) bool {
+	for _, line := range strings.Split(document, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitDocumentsSingleDocument(t *testing.T) {
+	for _, yaml := range []string{
+		"team: a\npipeline: b\n",
+		"---\nteam: a\npipeline: b\n",
+		"# comment\n---\nteam: a\n---\n# nothing here\n",
+	} {
+		assert.Equal(t, []string{yaml}, SplitDocuments(yaml))
+	}
+}
+
+func TestSplitDocumentsKeepsLineNumbers(t *testing.T) {
+	yaml := `team: a
+pipeline: one
+---
+team: a
+pipeline: two
+tasks:
+- type: run
+  script: |
+    echo ---
+  unknown: field
+--- # three
+team: a
+pipeline: three
+`
+	documents := SplitDocuments(yaml)
+	assert.Len(t, documents, 3)
+
+	man, errs := Parse(documents[0])
+	assert.Empty(t, errs)
+	assert.Equal(t, "one", man.Pipeline)
+
+	_, _, errs = ParseWithSourceMap(".halfpipe.io", documents[1])
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `.halfpipe.io:10:3: tasks[0] : unknown field "unknown"`, errs[0].Error())
+
+	man, errs = Parse(documents[2])
+	assert.Empty(t, errs)
+	assert.Equal(t, "three", man.Pipeline)
+}
@@ -8,12 +8,14 @@ const (
 	FeatureUpdatePipeline       = "update-pipeline"
 	FeatureUpdatePipelineAndTag = "update-pipeline-and-tag"
 	FeatureGithubStatuses       = "github-statuses"
+	FeatureCompositeActions     = "composite-actions"
 )
 
 var AvailableFeatureToggles = FeatureToggles{
 	FeatureUpdatePipeline,
 	FeatureUpdatePipelineAndTag,
 	FeatureGithubStatuses,
+	FeatureCompositeActions,
 }
 
 func (f FeatureToggles) UpdatePipeline() bool {
@@ -27,3 +29,9 @@ func (f FeatureToggles) TagGitRepo() bool {
 func (f FeatureToggles) GithubStatuses() bool {
 	return slices.Contains(f, FeatureGithubStatuses)
 }
+
+// CompositeActions renders the steps of deploy-cf and docker-push tasks in GitHub Actions as calls to the
+// composite actions released together with halfpipe instead of inlining them in the workflow
+func (f FeatureToggles) CompositeActions() bool {
+	return slices.Contains(f, FeatureCompositeActions)
+}
@@ -39,3 +39,10 @@ func (git GitTrigger) GetRepoName() string {
 
 	return strings.Split(repo, ".git")[0]
 }
+
+// GetRepoFullName is the owner and name of the repo on GitHub, e.g. 'springernature/halfpipe'
+func (git GitTrigger) GetRepoFullName() string {
+	uri := strings.TrimSuffix(git.URI, ".git")
+	uri = strings.TrimPrefix(uri, "git@github.com:")
+	return strings.TrimPrefix(uri, "https://github.com/")
+}
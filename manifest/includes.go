@@ -0,0 +1,313 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const (
+	includeKey   = "include"
+	varsFilesKey = "vars_files"
+)
+
+// ReadFile reads a file that the manifest refers to, the path is relative to the directory of the manifest
+type ReadFile func(path string) (string, error)
+
+// ParseWithIncludes parses the manifest like ParseWithSourceMap, after resolving 'include' and 'vars_files' with readFile.
+//
+// The top level fields of the included files are added to the manifest, fields the manifest sets itself are kept
+// and if several included files set a field the last one wins.
+// Every '${vars.name}' in the values of the manifest is replaced with the var 'name' from the vars files,
+// '$${vars.' is a literal '${vars.'. Other '${...}' are left as they are, so shell variables in scripts and vars still work.
+// If several vars files define a var the last one wins.
+//
+// Positions in the source map of included fields are in the included file and positions of values with vars
+// are the positions of the vars in the vars file, so lint errors say which file the value came from.
+func ParseWithIncludes(file string, manifestYaml string, readFile ReadFile) (Manifest, SourceMap, []error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte(manifestYaml), &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+		return ParseWithSourceMap(file, manifestYaml)
+	}
+
+	root := doc.Content[0]
+	includes, varsFiles := fileRefs(root, includeKey), fileRefs(root, varsFilesKey)
+	if includes == nil && varsFiles == nil {
+		return ParseWithSourceMap(file, manifestYaml)
+	}
+
+	r := includeResolver{file: file, readFile: readFile}
+	resolved, origins := manifestYaml, lineOrigins{}
+	if len(includes) > 0 {
+		resolved, origins = r.include(root, manifestYaml, includes)
+	}
+
+	var vars *variables
+	if varsFiles != nil {
+		vars = r.readVars(varsFiles)
+	}
+
+	if len(r.errs) > 0 {
+		return Manifest{}, NewSourceMap(file, []byte(manifestYaml)), r.errs
+	}
+
+	man, sourceMap, errs := parse(file, resolved, vars)
+	for path, pos := range sourceMap {
+		sourceMap[path] = origins.relocate(file, pos)
+	}
+	for i, err := range errs {
+		if located, ok := err.(LocatedError); ok {
+			located.Position = origins.relocate(file, located.Position)
+			errs[i] = located
+		}
+	}
+	return man, sourceMap, errs
+}
+
+// fileRef is a path in 'include' or 'vars_files'
+type fileRef struct {
+	field string
+	path  string
+	node  *yamlv3.Node
+}
+
+// fileRefs returns the paths in the list under the key, if it is not a list of paths the parser reports it
+func fileRefs(root *yamlv3.Node, key string) (refs []fileRef) {
+	_, list := mappingValue(root, key)
+	if list == nil || list.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+
+	refs = []fileRef{}
+	for i, item := range list.Content {
+		if item.Kind != yamlv3.ScalarNode {
+			return nil
+		}
+		refs = append(refs, fileRef{field: fmt.Sprintf("%s[%d]", key, i), path: item.Value, node: item})
+	}
+	return refs
+}
+
+// lineOrigins maps the lines that were added to the manifest from included files to their position in those files
+type lineOrigins map[int]Position
+
+func (o lineOrigins) relocate(file string, pos Position) Position {
+	origin, found := o[pos.Line]
+	if !found || pos.File != file {
+		return pos
+	}
+	origin.Column = pos.Column
+	return origin
+}
+
+type includeResolver struct {
+	file     string
+	readFile ReadFile
+	errs     []error
+}
+
+func errorIn(file string, node *yamlv3.Node, format string, a ...any) error {
+	return LocatedError{
+		Position: Position{File: file, Line: node.Line, Column: node.Column},
+		Err:      fmt.Errorf(format, a...),
+	}
+}
+
+// readYaml reads the file and returns its root map, or nil if it cannot be read
+func (r *includeResolver) readYaml(ref fileRef, contains string) (content string, root *yamlv3.Node) {
+	content, err := r.readFile(ref.path)
+	if err != nil {
+		r.errs = append(r.errs, errorIn(r.file, ref.node, "%s : %w", ref.field, err))
+		return "", nil
+	}
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte(content), &doc); err != nil {
+		r.errs = append(r.errs, LocatedError{Position: positionFromYAMLError(ref.path, err), Err: fmt.Errorf("%s : %w", ref.field, err)})
+		return "", nil
+	}
+
+	if len(doc.Content) == 0 {
+		return content, &yamlv3.Node{Kind: yamlv3.MappingNode}
+	}
+
+	root = doc.Content[0]
+	if root.Kind != yamlv3.MappingNode || root.Style&yamlv3.FlowStyle != 0 {
+		r.errs = append(r.errs, errorIn(ref.path, root, "%s : '%s' must be a map of %s", ref.field, ref.path, contains))
+		return "", nil
+	}
+	return content, root
+}
+
+// include appends the top level fields of the included files that the manifest does not set to the manifest.
+// The lines are copied as they are, so that the positions of all nodes in them can be mapped back to the included file.
+func (r *includeResolver) include(root *yamlv3.Node, manifestYaml string, includes []fileRef) (resolved string, origins lineOrigins) {
+	if root.Style&yamlv3.FlowStyle != 0 {
+		r.errs = append(r.errs, errorIn(r.file, includes[0].node, "%s : a manifest with includes must not be in flow style", includeKey))
+		return manifestYaml, nil
+	}
+
+	set := map[string]bool{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		set[root.Content[i].Value] = true
+	}
+
+	resolved = manifestYaml
+	if !strings.HasSuffix(resolved, "\n") {
+		resolved += "\n"
+	}
+	line := strings.Count(resolved, "\n")
+	origins = lineOrigins{}
+
+	// the last include wins, so they are added in reverse order and fields that are already set are skipped
+	for i := len(includes) - 1; i >= 0; i-- {
+		include := includes[i]
+		content, includedRoot := r.readYaml(include, "manifest fields")
+		if includedRoot == nil {
+			continue
+		}
+
+		lines := strings.SplitAfter(content, "\n")
+		keys := includedRoot.Content
+		for k := 0; k+1 < len(keys); k += 2 {
+			key := keys[k]
+			if key.Value == includeKey || key.Value == varsFilesKey {
+				r.errs = append(r.errs, errorIn(include.path, key, "%s : '%s' is not allowed in an included file", include.field, key.Value))
+				continue
+			}
+			if set[key.Value] {
+				continue
+			}
+			set[key.Value] = true
+
+			end := len(lines)
+			if k+2 < len(keys) {
+				end = keys[k+2].Line - 1
+			}
+			for l := key.Line - 1; l < end; l++ {
+				text := strings.TrimRight(lines[l], "\r\n")
+				if documentSeparator.MatchString(text) || text == "..." {
+					break
+				}
+				resolved += text + "\n"
+				line++
+				origins[line] = Position{File: include.path, Line: l + 1}
+			}
+		}
+	}
+	return resolved, origins
+}
+
+type variable struct {
+	value    string
+	position Position
+}
+
+// variables are the vars from the vars files, nested maps are flattened to 'parent.child'
+type variables struct {
+	files  []string
+	values map[string]variable
+}
+
+func (r *includeResolver) readVars(varsFiles []fileRef) *variables {
+	vars := &variables{values: map[string]variable{}}
+	for _, varsFile := range varsFiles {
+		vars.files = append(vars.files, varsFile.path)
+		if _, root := r.readYaml(varsFile, "vars"); root != nil {
+			r.addVars(vars, varsFile, "", root)
+		}
+	}
+	return vars
+}
+
+func (r *includeResolver) addVars(vars *variables, varsFile fileRef, prefix string, node *yamlv3.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		name := joinPath(prefix, key.Value)
+		switch {
+		case value.Kind == yamlv3.MappingNode:
+			r.addVars(vars, varsFile, name, value)
+		case value.Kind == yamlv3.ScalarNode && value.Tag == "!!null":
+			vars.values[name] = variable{position: Position{File: varsFile.path, Line: value.Line, Column: value.Column}}
+		case value.Kind == yamlv3.ScalarNode:
+			vars.values[name] = variable{value: value.Value, position: Position{File: varsFile.path, Line: value.Line, Column: value.Column}}
+		default:
+			r.errs = append(r.errs, errorIn(varsFile.path, value, "%s : var '%s' must be a string, number, boolean or a map of vars", varsFile.field, name))
+		}
+	}
+}
+
+// varReference matches '${vars.name}', the 'vars.' prefix is not valid in shell parameter expansion
+var varReference = regexp.MustCompile(`\$(\$?)\{vars\.([A-Za-z0-9_.-]+)\}`)
+
+// substitute replaces the vars in all values of the manifest json.
+// The values that have vars are mapped to the position of the first var in them in the source map.
+func (v *variables) substitute(js []byte, sourceMap SourceMap) ([]byte, []error) {
+	if v == nil {
+		return js, nil
+	}
+
+	var config any
+	decoder := json.NewDecoder(bytes.NewReader(js))
+	decoder.UseNumber()
+	if err := decoder.Decode(&config); err != nil {
+		return js, nil
+	}
+
+	var errs []error
+	config = v.substituteValue("", config, sourceMap, &errs)
+	if len(errs) > 0 {
+		return js, errs
+	}
+
+	substituted, err := json.Marshal(config)
+	if err != nil {
+		return js, []error{err}
+	}
+	return substituted, nil
+}
+
+func (v *variables) substituteValue(path string, value any, sourceMap SourceMap, errs *[]error) any {
+	switch value := value.(type) {
+	case map[string]any:
+		var keys []string
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value[key] = v.substituteValue(joinPath(path, key), value[key], sourceMap, errs)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = v.substituteValue(fmt.Sprintf("%s[%d]", path, i), item, sourceMap, errs)
+		}
+	case string:
+		var origin Position
+		substituted := varReference.ReplaceAllStringFunc(value, func(ref string) string {
+			m := varReference.FindStringSubmatch(ref)
+			if m[1] != "" {
+				return ref[1:]
+			}
+			variable, found := v.values[m[2]]
+			if !found {
+				*errs = append(*errs, sourceMap.Locate(path, fmt.Errorf("%s : var '%s' is not defined in %s", path, m[2], strings.Join(v.files, ", "))))
+				return ref
+			}
+			if origin.IsZero() {
+				origin = variable.position
+			}
+			return variable.value
+		})
+		if !origin.IsZero() {
+			sourceMap[path] = origin
+		}
+		return substituted
+	}
+	return value
+}
@@ -0,0 +1,202 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readFiles(files map[string]string) ReadFile {
+	return func(path string) (string, error) {
+		if content, found := files[path]; found {
+			return content, nil
+		}
+		return "", fmt.Errorf("file not found '%s'", path)
+	}
+}
+
+var sharedFiles = readFiles(map[string]string{
+	"../shared/cf.yml": `cf:
+  api: ((cloudfoundry.api-snpaas))
+  org: my-org
+channel: "#ee-activity"
+`,
+	"../shared/notifications.yml": `# shared notifications
+notifications:
+  failure:
+  - slack: "#ee-re"
+triggers:
+- type: timer
+  cron: "0 1 * * *"
+`,
+	"local.yml": `cf:
+  org: local-org
+`,
+})
+
+func TestVarsAreSubstituted(t *testing.T) {
+	manifestYaml := `team: my team
+pipeline: my pipeline
+vars_files:
+- ../shared/cf.yml
+- local.yml
+tasks:
+- type: deploy-cf
+  name: deploy
+  api: ${vars.cf.api}
+  org: ${vars.cf.org}
+  space: live
+  vars:
+    CHANNEL: ${vars.channel}
+    SCRIPT: echo ${HOME} $${vars.channel}
+`
+
+	man, sourceMap, errs := ParseWithIncludes(".halfpipe.io", manifestYaml, sharedFiles)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"../shared/cf.yml", "local.yml"}, man.VarsFiles)
+	assert.Equal(t, TaskList{DeployCF{
+		Name:  "deploy",
+		API:   "((cloudfoundry.api-snpaas))",
+		Org:   "local-org",
+		Space: "live",
+		Vars:  Vars{"CHANNEL": "#ee-activity", "SCRIPT": "echo ${HOME} ${vars.channel}"},
+	}}, man.Tasks)
+
+	assert.Equal(t, Position{File: "../shared/cf.yml", Line: 2, Column: 8}, sourceMap["tasks[0].api"])
+	assert.Equal(t, Position{File: "local.yml", Line: 2, Column: 8}, sourceMap["tasks[0].org"])
+	assert.Equal(t, Position{File: ".halfpipe.io", Line: 11, Column: 3}, sourceMap["tasks[0].space"])
+}
+
+func TestUndefinedVar(t *testing.T) {
+	manifestYaml := `team: my team
+pipeline: my pipeline
+vars_files:
+- ../shared/cf.yml
+tasks:
+- type: run
+  name: test
+  script: ./test.sh
+  docker:
+    image: ${vars.image}
+`
+
+	_, _, errs := ParseWithIncludes(".halfpipe.io", manifestYaml, sharedFiles)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, ".halfpipe.io:10:5: tasks[0].docker.image : var 'image' is not defined in ../shared/cf.yml", errs[0].Error())
+}
+
+func TestVarsAreOnlySubstitutedWithVarsFiles(t *testing.T) {
+	manifestYaml := `team: my team
+pipeline: my pipeline
+tasks:
+- type: run
+  name: test
+  script: ./test.sh
+  docker:
+    image: alpine
+  vars:
+    PATH: ${PATH}:/bin
+`
+
+	man, _, errs := ParseWithIncludes(".halfpipe.io", manifestYaml, sharedFiles)
+	assert.Empty(t, errs)
+	assert.Equal(t, Vars{"PATH": "${PATH}:/bin"}, man.Tasks[0].(Run).Vars)
+}
+
+func TestIncludes(t *testing.T) {
+	manifestYaml := `team: my team
+pipeline: my pipeline
+include:
+- ../shared/notifications.yml
+triggers:
+- type: git
+  watched_paths:
+  - src
+tasks:
+- type: run
+  name: test
+  script: ./test.sh
+  docker:
+    image: alpine
+`
+
+	man, sourceMap, errs := ParseWithIncludes(".halfpipe.io", manifestYaml, sharedFiles)
+	assert.Empty(t, errs)
+	assert.Equal(t, NotificationChannels{{Slack: "#ee-re"}}, man.Notifications.Failure)
+	assert.Equal(t, TriggerList{GitTrigger{WatchedPaths: []string{"src"}}}, man.Triggers, "fields the manifest sets are not included")
+
+	assert.Equal(t, Position{File: "../shared/notifications.yml", Line: 2, Column: 1}, sourceMap["notifications"])
+	assert.Equal(t, Position{File: "../shared/notifications.yml", Line: 4, Column: 5}, sourceMap["notifications.failure[0].slack"])
+	assert.Equal(t, Position{File: ".halfpipe.io", Line: 11, Column: 3}, sourceMap["tasks[0].name"])
+}
+
+func TestIncludesWithVarsAndTemplates(t *testing.T) {
+	files := readFiles(map[string]string{
+		"templates.yml": `templates:
+  deploy:
+    type: deploy-cf
+    api: ${vars.cf.api}
+    space: live
+    manifest: manifest.yml
+`,
+		"cf.yml": `cf:
+  api: api.example.com
+`,
+	})
+
+	manifestYaml := `team: my team
+pipeline: my pipeline
+include:
+- templates.yml
+vars_files:
+- cf.yml
+tasks:
+- name: deploy
+  extends: deploy
+  org: my-org
+`
+
+	man, sourceMap, errs := ParseWithIncludes(".halfpipe.io", manifestYaml, files)
+	assert.Empty(t, errs)
+	assert.Equal(t, TaskList{DeployCF{Name: "deploy", API: "api.example.com", Org: "my-org", Space: "live", Manifest: "manifest.yml"}}, man.Tasks)
+	assert.Equal(t, Position{File: "cf.yml", Line: 2, Column: 8}, sourceMap["tasks[0].api"])
+	assert.Equal(t, Position{File: "templates.yml", Line: 5, Column: 5}, sourceMap["tasks[0].space"])
+}
+
+func TestIncludeErrors(t *testing.T) {
+	files := readFiles(map[string]string{
+		"nested.yml": "include:\n- other.yml\n",
+		"list.yml":   "- a\n- b\n",
+		"broken.yml": "team: a\n  b: c\n",
+		"vars.yml":   "list:\n- a\n",
+	})
+
+	for _, test := range []struct {
+		manifest string
+		err      string
+	}{
+		{"include:\n- missing.yml\n", ".halfpipe.io:4:3: include[0] : file not found 'missing.yml'"},
+		{"include:\n- nested.yml\n", "nested.yml:1:1: include[0] : 'include' is not allowed in an included file"},
+		{"include:\n- list.yml\n", "list.yml:1:1: include[0] : 'list.yml' must be a map of manifest fields"},
+		{"include:\n- broken.yml\n", "broken.yml:2: include[0] : yaml: line 2: mapping values are not allowed in this context"},
+		{"vars_files:\n- vars.yml\n", "vars.yml:2:1: vars_files[0] : var 'list' must be a string, number, boolean or a map of vars"},
+	} {
+		_, _, errs := ParseWithIncludes(".halfpipe.io", "team: a\npipeline: b\n"+test.manifest, files)
+		if assert.Len(t, errs, 1, test.manifest) {
+			assert.Equal(t, test.err, errs[0].Error())
+			assert.True(t, errors.As(errs[0], &LocatedError{}))
+		}
+	}
+}
+
+func TestErrorsInIncludedFieldsAreInTheIncludedFile(t *testing.T) {
+	files := readFiles(map[string]string{
+		"shared.yml": "# shared triggers\ntriggers:\n- type: timer\n  cronn: '0 1 * * *'\n",
+	})
+
+	_, _, errs := ParseWithIncludes(".halfpipe.io", "team: a\npipeline: b\ninclude:\n- shared.yml\n", files)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "shared.yml:4:3: ")
+}
@@ -164,7 +164,10 @@ type Task interface {
 	SavesArtifacts() bool
 	SavesArtifactsOnFailure() bool
 	IsManualTrigger() bool
+	SkipsOnPullRequest() bool
 	NotifiesOnSuccess() bool
+	GetRunsOn() []string
+	GetWatchedPaths() []string
 
 	GetTimeout() string
 	SetTimeout(timeout string) Task
@@ -212,6 +215,33 @@ func (t TriggerList) HasGitTrigger() bool {
 	return false
 }
 
+func (t TriggerList) HasPullRequestTrigger() bool {
+	for _, trigger := range t {
+		if _, ok := trigger.(PullRequestTrigger); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (t TriggerList) GetTagTrigger() TagTrigger {
+	for _, trigger := range t {
+		if tag, ok := trigger.(TagTrigger); ok {
+			return tag
+		}
+	}
+	return TagTrigger{}
+}
+
+func (t TriggerList) HasTagTrigger() bool {
+	for _, trigger := range t {
+		if _, ok := trigger.(TagTrigger); ok {
+			return true
+		}
+	}
+	return false
+}
+
 type Platform string
 
 func (p Platform) IsActions() bool {
@@ -235,6 +265,9 @@ type Manifest struct {
 	Tasks               TaskList       `yaml:"tasks,omitempty"`
 	Platform            Platform       `json:"platform,omitempty" yaml:"platform,omitempty"`
 	Notifications       Notifications  `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	VarsFiles           []string       `json:"vars_files,omitempty" yaml:"vars_files,omitempty"`
+	Include             []string       `json:"include,omitempty" yaml:"include,omitempty"`
+	DispatchJobStatus   []string       `json:"dispatch_job_status,omitempty" yaml:"dispatch_job_status,omitempty"`
 }
 
 func (m Manifest) PipelineName() (pipelineName string) {
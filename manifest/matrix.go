@@ -0,0 +1,361 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	MatrixModeParallel = "parallel"
+	MatrixModeSequence = "sequence"
+)
+
+type MatrixConfig struct {
+	Mode    string                   `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Include []map[string]interface{} `json:"include,omitempty" yaml:"include,omitempty"`
+}
+
+// Matrix is a task that is run once for every entry in 'matrix.include', each entry overrides fields of the task.
+// It only exists until the defaulter has expanded it into concrete tasks.
+type Matrix struct {
+	Task   Task
+	Matrix MatrixConfig
+
+	rawTask  json.RawMessage
+	include  []json.RawMessage
+	taskType string
+}
+
+func (m Matrix) IsSequence() bool {
+	return m.Matrix.Mode == MatrixModeSequence
+}
+
+// Expand returns one task per entry in 'matrix.include'.
+// Unless the entry sets 'name' the task is named after the values in the entry, e.g. 'deploy (qa)'
+func (m Matrix) Expand() (tasks TaskList, err error) {
+	for i, include := range m.include {
+		task, err := decodeTask(mergeJSON(m.rawTask, include), m.taskType)
+		if err != nil {
+			return nil, errorAtPath(fmt.Sprintf("matrix.include[%v]", i), fmt.Errorf("matrix.include[%v] : %w", i, err))
+		}
+
+		if _, found := m.Matrix.Include[i]["name"]; !found {
+			task = task.SetName(fmt.Sprintf("%s (%s)", m.Task.GetName(), matrixLabel(i, m.Matrix.Include[i])))
+		}
+
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// ExpandMatrices replaces matrix tasks with the tasks they expand to.
+// When the tasks should run the same way as the list the matrix is in they are added directly to the list,
+// otherwise they are wrapped in a parallel or a sequence.
+func (tl TaskList) ExpandMatrices() TaskList {
+	return expandMatrices(tl, sequenceList, "tasks", "tasks", func(from, to string, children ...string) {})
+}
+
+// taskListKind is how the tasks in a list run
+type taskListKind int
+
+const (
+	sequenceList taskListKind = iota
+	parallelList
+	// pre promote tasks always run one after another, so the tasks of a matrix are added directly to the list
+	prePromoteList
+)
+
+// expandMatrices calls moved for every task with the path it had in the manifest and the path it gets after expansion,
+// children are the fields of the task that are moved separately
+func expandMatrices(tasks TaskList, kind taskListKind, fromPath string, toPath string, moved func(from, to string, children ...string)) (updated TaskList) {
+	for i, task := range tasks {
+		from := fmt.Sprintf("%s[%d]", fromPath, i)
+		to := fmt.Sprintf("%s[%d]", toPath, len(updated))
+
+		switch task := task.(type) {
+		case Matrix:
+			expanded, err := task.Expand()
+			if err != nil {
+				// the matrix has already been validated by the parser, leave it to the linter to complain
+				moved(from, to)
+				updated = append(updated, task)
+				continue
+			}
+
+			wrap := kind != prePromoteList && task.IsSequence() == (kind == parallelList)
+			if wrap {
+				moved(from, to, "matrix")
+			}
+			for j := range expanded {
+				expandedTo := fmt.Sprintf("%s[%d]", toPath, len(updated)+j)
+				if wrap {
+					expandedTo = fmt.Sprintf("%s.tasks[%d]", to, j)
+				}
+
+				// the pre_promote of a matrix deploy-cf can have matrix tasks too
+				deploy, isDeploy := expanded[j].(DeployCF)
+				if isDeploy {
+					moved(from, expandedTo, "matrix", "pre_promote")
+				} else {
+					moved(from, expandedTo, "matrix")
+				}
+				moved(fmt.Sprintf("%s.matrix.include[%d]", from, j), expandedTo)
+				if isDeploy {
+					deploy.PrePromote = expandMatrices(deploy.PrePromote, prePromoteList, from+".pre_promote", expandedTo+".pre_promote", moved)
+					expanded[j] = deploy
+				}
+			}
+
+			switch {
+			case wrap && kind == parallelList:
+				updated = append(updated, Sequence{Tasks: expanded})
+			case wrap:
+				updated = append(updated, Parallel{Tasks: expanded})
+			default:
+				updated = append(updated, expanded...)
+			}
+		case Parallel:
+			moved(from, to, "tasks")
+			task.Tasks = expandMatrices(task.Tasks, parallelList, from+".tasks", to+".tasks", moved)
+			updated = append(updated, task)
+		case Sequence:
+			moved(from, to, "tasks")
+			task.Tasks = expandMatrices(task.Tasks, sequenceList, from+".tasks", to+".tasks", moved)
+			updated = append(updated, task)
+		case DeployCF:
+			moved(from, to, "pre_promote")
+			task.PrePromote = expandMatrices(task.PrePromote, prePromoteList, from+".pre_promote", to+".pre_promote", moved)
+			updated = append(updated, task)
+		default:
+			moved(from, to)
+			updated = append(updated, task)
+		}
+	}
+	return updated
+}
+
+// withExpandedMatrices returns the source map for the paths the tasks get after the matrices are expanded,
+// fields set in a matrix entry point to the entry and all other fields to the task
+func (s SourceMap) withExpandedMatrices(tasks TaskList) SourceMap {
+	updated := SourceMap{}
+	for path, pos := range s {
+		if !isSubPath(path, "tasks") || path == "tasks" {
+			updated[path] = pos
+		}
+	}
+
+	// the task is moved before the matrix entry that overrides its fields
+	expandMatrices(tasks, sequenceList, "tasks", "tasks", func(from, to string, children ...string) {
+		for path, pos := range s {
+			if isSubPath(path, from) && !slices.ContainsFunc(children, func(child string) bool { return isSubPath(path, from+"."+child) }) {
+				updated[to+path[len(from):]] = pos
+			}
+		}
+	})
+	return updated
+}
+
+func isSubPath(path string, parent string) bool {
+	return path == parent || strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+"[")
+}
+
+func matrixLabel(index int, include map[string]interface{}) string {
+	var keys []string
+	for key := range include {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var values []string
+	for _, key := range keys {
+		switch value := include[key].(type) {
+		case string, bool, json.Number, float64:
+			values = append(values, fmt.Sprint(value))
+		}
+	}
+
+	if len(values) == 0 {
+		return fmt.Sprint(index + 1)
+	}
+	return strings.Join(values, ", ")
+}
+
+// mergeJSON overrides the fields in base with the fields in override, objects are merged
+func mergeJSON(base json.RawMessage, override json.RawMessage) json.RawMessage {
+	var baseFields, overrideFields map[string]json.RawMessage
+	if json.Unmarshal(base, &baseFields) != nil || json.Unmarshal(override, &overrideFields) != nil {
+		return override
+	}
+
+	if baseFields == nil {
+		baseFields = map[string]json.RawMessage{}
+	}
+	for key, value := range overrideFields {
+		if existing, found := baseFields[key]; found && isJSONObject(existing) && isJSONObject(value) {
+			value = mergeJSON(existing, value)
+		}
+		baseFields[key] = value
+	}
+
+	merged, _ := json.Marshal(baseFields)
+	return merged
+}
+
+func isJSONObject(js json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(js), []byte("{"))
+}
+
+func unmarshalMatrix(rawTask json.RawMessage, taskType string) (matrix Matrix, isMatrix bool, err error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rawTask, &fields); err != nil {
+		return matrix, false, nil
+	}
+
+	rawMatrix, isMatrix := fields["matrix"]
+	if !isMatrix {
+		return matrix, false, nil
+	}
+	delete(fields, "matrix")
+
+	if taskType == "parallel" || taskType == "sequence" {
+		return matrix, true, errorAtPath("matrix", fmt.Errorf("matrix : not supported on '%s' tasks", taskType))
+	}
+
+	var config struct {
+		Mode    string            `json:"mode"`
+		Include []json.RawMessage `json:"include"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(rawMatrix))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return matrix, true, errorAtPath("matrix", fmt.Errorf("matrix : %w", jsonError(err)))
+	}
+
+	if config.Mode != "" && config.Mode != MatrixModeParallel && config.Mode != MatrixModeSequence {
+		return matrix, true, errorAtPath("matrix.mode", fmt.Errorf("matrix.mode : must be either '%s' or '%s'", MatrixModeParallel, MatrixModeSequence))
+	}
+
+	if len(config.Include) == 0 {
+		return matrix, true, errorAtPath("matrix.include", fmt.Errorf("matrix.include : must contain at least one entry"))
+	}
+
+	matrix.rawTask, _ = json.Marshal(fields)
+	matrix.include = config.Include
+	matrix.taskType = taskType
+	matrix.Matrix.Mode = config.Mode
+
+	if matrix.Task, err = decodeTask(matrix.rawTask, taskType); err != nil {
+		return matrix, true, err
+	}
+
+	for i, include := range config.Include {
+		var values map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(include))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil {
+			return matrix, true, errorAtPath(fmt.Sprintf("matrix.include[%v]", i), fmt.Errorf("matrix.include[%v] : %w", i, jsonError(err)))
+		}
+		matrix.Matrix.Include = append(matrix.Matrix.Include, values)
+
+		if _, err := decodeTask(mergeJSON(matrix.rawTask, include), taskType); err != nil {
+			return matrix, true, errorAtPath(fmt.Sprintf("matrix.include[%v]", i), fmt.Errorf("matrix.include[%v] : %w", i, err))
+		}
+	}
+
+	return matrix, true, nil
+}
+
+func (m Matrix) MarshalYAML() (interface{}, error) {
+	task, err := yaml.Marshal(m.Task)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields yaml.MapSlice
+	if err := yaml.Unmarshal(task, &fields); err != nil {
+		return nil, err
+	}
+	return append(fields, yaml.MapItem{Key: "matrix", Value: m.Matrix}), nil
+}
+
+func (m Matrix) ReadsFromArtifacts() bool {
+	return m.Task.ReadsFromArtifacts()
+}
+
+func (m Matrix) GetAttempts() int {
+	return m.Task.GetAttempts()
+}
+
+func (m Matrix) SavesArtifacts() bool {
+	return m.Task.SavesArtifacts()
+}
+
+func (m Matrix) SavesArtifactsOnFailure() bool {
+	return m.Task.SavesArtifactsOnFailure()
+}
+
+func (m Matrix) IsManualTrigger() bool {
+	return m.Task.IsManualTrigger()
+}
+
+func (m Matrix) SkipsOnPullRequest() bool {
+	return m.Task.SkipsOnPullRequest()
+}
+
+func (m Matrix) GetRunsOn() []string {
+	return m.Task.GetRunsOn()
+}
+
+func (m Matrix) GetWatchedPaths() []string {
+	return m.Task.GetWatchedPaths()
+}
+
+func (m Matrix) NotifiesOnSuccess() bool {
+	return m.Task.NotifiesOnSuccess()
+}
+
+func (m Matrix) GetTimeout() string {
+	return m.Task.GetTimeout()
+}
+
+func (m Matrix) SetTimeout(timeout string) Task {
+	panic("SetTimeout should never be used on a matrix task as it is expanded by the defaulter")
+}
+
+func (m Matrix) GetName() string {
+	return m.Task.GetName()
+}
+
+func (m Matrix) SetName(name string) Task {
+	panic("SetName should never be used on a matrix task as it is expanded by the defaulter")
+}
+
+func (m Matrix) GetNotifications() Notifications {
+	return m.Task.GetNotifications()
+}
+
+func (m Matrix) SetNotifications(notifications Notifications) Task {
+	panic("SetNotifications should never be used on a matrix task as it is expanded by the defaulter")
+}
+
+func (m Matrix) SetNotifyOnSuccess(notifyOnSuccess bool) Task {
+	panic("SetNotifyOnSuccess should never be used on a matrix task as it is expanded by the defaulter")
+}
+
+func (m Matrix) GetBuildHistory() int {
+	return m.Task.GetBuildHistory()
+}
+
+func (m Matrix) SetBuildHistory(buildHistory int) Task {
+	panic("SetBuildHistory should never be used on a matrix task as it is expanded by the defaulter")
+}
+
+func (m Matrix) GetSecrets() map[string]string {
+	return m.Task.GetSecrets()
+}
@@ -0,0 +1,185 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const matrixManifest = `tasks:
+- type: run
+  name: test
+  script: ./test.sh
+  matrix:
+    mode: sequence
+    include:
+    - vars:
+        JAVA: 17
+    - vars:
+        JAVA: 21
+- type: deploy-cf
+  name: deploy
+  manifest: manifest.yml
+  vars:
+    A: a
+  matrix:
+    include:
+    - space: qa
+    - space: live
+      vars:
+        B: b
+    - name: custom name
+- type: parallel
+  tasks:
+  - type: run
+    script: ./lint.sh
+  - type: deploy-katee
+    name: katee
+    matrix:
+      include:
+      - namespace: katee-qa
+      - namespace: katee-live
+  - type: deploy-katee
+    name: katee sequence
+    matrix:
+      mode: sequence
+      include:
+      - namespace: katee-qa
+      - namespace: katee-live
+`
+
+func TestMatrixIsExpanded(t *testing.T) {
+	man, errs := Parse(matrixManifest)
+	assert.Empty(t, errs)
+
+	expected := TaskList{
+		Run{Name: "test (1)", Script: "./test.sh", Vars: Vars{"JAVA": "17"}},
+		Run{Name: "test (2)", Script: "./test.sh", Vars: Vars{"JAVA": "21"}},
+		Parallel{Tasks: TaskList{
+			DeployCF{Name: "deploy (qa)", Manifest: "manifest.yml", Space: "qa", Vars: Vars{"A": "a"}},
+			DeployCF{Name: "deploy (live)", Manifest: "manifest.yml", Space: "live", Vars: Vars{"A": "a", "B": "b"}},
+			DeployCF{Name: "custom name", Manifest: "manifest.yml", Vars: Vars{"A": "a"}},
+		}},
+		Parallel{Tasks: TaskList{
+			Run{Script: "./lint.sh"},
+			DeployKatee{Name: "katee (katee-qa)", Namespace: "katee-qa"},
+			DeployKatee{Name: "katee (katee-live)", Namespace: "katee-live"},
+			Sequence{Tasks: TaskList{
+				DeployKatee{Name: "katee sequence (katee-qa)", Namespace: "katee-qa"},
+				DeployKatee{Name: "katee sequence (katee-live)", Namespace: "katee-live"},
+			}},
+		}},
+	}
+
+	assert.Equal(t, expected, man.Tasks.ExpandMatrices())
+}
+
+func TestMatrixSourceMapPointsToTheOriginalTasks(t *testing.T) {
+	_, sourceMap, errs := ParseWithSourceMap(".halfpipe.io", matrixManifest)
+	assert.Empty(t, errs)
+
+	tests := map[string]string{
+		"tasks[0]":                 ".halfpipe.io:8:7",
+		"tasks[0].script":          ".halfpipe.io:4:3",
+		"tasks[1].vars.JAVA":       ".halfpipe.io:11:9",
+		"tasks[2]":                 ".halfpipe.io:12:3",
+		"tasks[2][0].space":        ".halfpipe.io:19:7",
+		"tasks[2][1].vars.A":       ".halfpipe.io:16:5",
+		"tasks[2][1].vars.B":       ".halfpipe.io:22:9",
+		"tasks[2][2].manifest":     ".halfpipe.io:14:3",
+		"tasks[3][0].script":       ".halfpipe.io:27:5",
+		"tasks[3][2].namespace":    ".halfpipe.io:33:9",
+		"tasks[3][3]":              ".halfpipe.io:34:5",
+		"tasks[3][3][1].namespace": ".halfpipe.io:40:9",
+		"tasks[3][3][1].unknown":   ".halfpipe.io:40:9",
+		"tasks[3][3][0].name":      ".halfpipe.io:35:5",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			pos, found := sourceMap.Lookup(path)
+			assert.True(t, found)
+			assert.Equal(t, expected, pos.String())
+		})
+	}
+}
+
+func TestMatrixErrors(t *testing.T) {
+	tests := map[string]struct {
+		yaml     string
+		expected string
+	}{
+		"unknown field in matrix": {
+			yaml:     "tasks:\n- type: run\n  matrix:\n    blah: true\n",
+			expected: `.halfpipe.io:4:5: tasks[0] : matrix : unknown field "blah"`,
+		},
+		"unknown mode": {
+			yaml:     "tasks:\n- type: run\n  matrix:\n    mode: blah\n    include:\n    - name: a\n",
+			expected: ".halfpipe.io:4:5: tasks[0] : matrix.mode : must be either 'parallel' or 'sequence'",
+		},
+		"no entries": {
+			yaml:     "tasks:\n- type: run\n  matrix:\n    include: []\n",
+			expected: ".halfpipe.io:4:5: tasks[0] : matrix.include : must contain at least one entry",
+		},
+		"unknown field in entry": {
+			yaml:     "tasks:\n- type: run\n  matrix:\n    include:\n    - name: a\n    - blah: a\n",
+			expected: `.halfpipe.io:6:7: tasks[0] : matrix.include[1] : unknown field "blah"`,
+		},
+		"matrix on parallel": {
+			yaml:     "tasks:\n- type: parallel\n  matrix:\n    include:\n    - name: a\n",
+			expected: ".halfpipe.io:3:3: tasks[0] : matrix : not supported on 'parallel' tasks",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, errs := ParseWithSourceMap(".halfpipe.io", test.yaml)
+			if assert.Len(t, errs, 1) {
+				assert.EqualError(t, errs[0], test.expected)
+			}
+		})
+	}
+}
+
+func TestMatrixInThePrePromoteOfAMatrixIsExpanded(t *testing.T) {
+	man, sourceMap, errs := ParseWithSourceMap(".halfpipe.io", `tasks:
+- type: deploy-cf
+  name: deploy
+  matrix:
+    include:
+    - space: qa
+    - space: live
+  pre_promote:
+  - type: run
+    name: smoke
+    script: ./smoke.sh
+    matrix:
+      include:
+      - vars:
+          BROWSER: chrome
+      - vars:
+          BROWSER: firefox
+`)
+	assert.Empty(t, errs)
+
+	prePromote := TaskList{
+		Run{Name: "smoke (1)", Script: "./smoke.sh", Vars: Vars{"BROWSER": "chrome"}},
+		Run{Name: "smoke (2)", Script: "./smoke.sh", Vars: Vars{"BROWSER": "firefox"}},
+	}
+	assert.Equal(t, TaskList{Parallel{Tasks: TaskList{
+		DeployCF{Name: "deploy (qa)", Space: "qa", PrePromote: prePromote},
+		DeployCF{Name: "deploy (live)", Space: "live", PrePromote: prePromote},
+	}}}, man.Tasks.ExpandMatrices())
+
+	for path, expected := range map[string]string{
+		"tasks[0][1].space":                       ".halfpipe.io:7:7",
+		"tasks[0][1].pre_promote[0].script":       ".halfpipe.io:11:5",
+		"tasks[0][1].pre_promote[1].vars":         ".halfpipe.io:16:9",
+		"tasks[0][0].pre_promote[1].unknown":      ".halfpipe.io:16:9",
+		"tasks[0][0].pre_promote[0].vars.BROWSER": ".halfpipe.io:15:11",
+	} {
+		pos, found := sourceMap.Lookup(path)
+		assert.True(t, found, path)
+		assert.Equal(t, expected, pos.String(), path)
+	}
+}
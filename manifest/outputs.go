@@ -0,0 +1,75 @@
+package manifest
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OutputReference is a reference in the vars of a task to an output of a previous task, '${{ outputs.<task>.<key> }}'.
+// The task is referred to by its ID, the name of the task in lower case where every character other than a-z, 0-9, '-' and '_' is replaced with '_'
+type OutputReference struct {
+	Task string
+	Key  string
+}
+
+var outputReference = regexp.MustCompile(`\$\{\{\s*outputs\.([a-z0-9_\-]+)\.([A-Za-z0-9_\-]+)\s*}}`)
+
+// TaskID is the ID a task is referred to by in output references, it is the same as the ID of the job in GitHub Actions
+func TaskID(name string) string {
+	return regexp.MustCompile(`[^a-z_0-9\-]`).ReplaceAllString(strings.ToLower(name), "_")
+}
+
+// ReplaceOutputReferences replaces every output reference in the value with what replace returns for it
+func ReplaceOutputReferences(value string, replace func(ref OutputReference) string) string {
+	return outputReference.ReplaceAllStringFunc(value, func(match string) string {
+		groups := outputReference.FindStringSubmatch(match)
+		return replace(OutputReference{Task: groups[1], Key: groups[2]})
+	})
+}
+
+// TaskOutputs returns the outputs a task declares, key to file relative to the manifest
+func TaskOutputs(task Task) map[string]string {
+	switch task := task.(type) {
+	case Run:
+		return task.Outputs
+	case DockerCompose:
+		return task.Outputs
+	}
+	return nil
+}
+
+// TaskOutputReferences returns the references to outputs of other tasks in the vars of a task
+func TaskOutputReferences(task Task) (refs []OutputReference) {
+	var vars Vars
+	switch task := task.(type) {
+	case Run:
+		vars = task.Vars
+	case DockerCompose:
+		vars = task.Vars
+	}
+
+	for _, value := range vars {
+		refs = append(refs, OutputReferences(value)...)
+	}
+	return refs
+}
+
+// OutputReferences returns the references to outputs of other tasks in a value
+func OutputReferences(value string) (refs []OutputReference) {
+	ReplaceOutputReferences(value, func(ref OutputReference) string {
+		refs = append(refs, ref)
+		return ""
+	})
+	return refs
+}
+
+// OutputFile returns the file of the output that is referenced
+func (tl TaskList) OutputFile(ref OutputReference) (string, bool) {
+	for _, task := range tl.Flatten() {
+		if TaskID(task.GetName()) == ref.Task {
+			file, found := TaskOutputs(task)[ref.Key]
+			return file, found
+		}
+	}
+	return "", false
+}
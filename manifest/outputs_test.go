@@ -0,0 +1,39 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputReferences(t *testing.T) {
+	value := "${{ outputs.build_app.version }}@${{outputs.push.digest}}"
+
+	assert.Equal(t, []OutputReference{{Task: "build_app", Key: "version"}, {Task: "push", Key: "digest"}}, OutputReferences(value))
+	assert.Empty(t, OutputReferences("${{ secrets.token }}"))
+
+	replaced := ReplaceOutputReferences(value, func(ref OutputReference) string {
+		return ref.Task + "/" + ref.Key
+	})
+	assert.Equal(t, "build_app/version@push/digest", replaced)
+}
+
+func TestOutputFile(t *testing.T) {
+	tasks := TaskList{
+		Run{Name: "Build App", Outputs: map[string]string{"version": "target/version"}},
+		Parallel{Tasks: TaskList{
+			DockerCompose{Name: "push", Outputs: map[string]string{"digest": "digest.txt"}},
+		}},
+	}
+
+	file, found := tasks.OutputFile(OutputReference{Task: "build_app", Key: "version"})
+	assert.True(t, found)
+	assert.Equal(t, "target/version", file)
+
+	file, found = tasks.OutputFile(OutputReference{Task: "push", Key: "digest"})
+	assert.True(t, found)
+	assert.Equal(t, "digest.txt", file)
+
+	_, found = tasks.OutputFile(OutputReference{Task: "push", Key: "version"})
+	assert.False(t, found)
+}
@@ -60,6 +60,18 @@ func (Parallel) IsManualTrigger() bool {
 	panic("IsManualTrigger should never be used in the rendering for a parallel task as we only care about sub tasks")
 }
 
+func (Parallel) SkipsOnPullRequest() bool {
+	panic("SkipsOnPullRequest should never be used in the rendering for a parallel task as we only care about sub tasks")
+}
+
+func (Parallel) GetRunsOn() []string {
+	panic("GetRunsOn should never be used in the rendering for a parallel task as we only care about sub tasks")
+}
+
+func (Parallel) GetWatchedPaths() []string {
+	panic("GetWatchedPaths should never be used in the rendering for a parallel task as we only care about sub tasks")
+}
+
 func (p Parallel) NotifiesOnSuccess() bool {
 	panic("NotifiesOnSuccess should never be used in the rendering for a parallel task as we only care about sub tasks")
 }
@@ -3,19 +3,130 @@ package manifest
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sigs.k8s.io/yaml"
+	"strconv"
 	"strings"
 )
 
 func Parse(manifestYaml string) (Manifest, []error) {
-	var man Manifest
-	errs := unmarshalAsJSON([]byte(manifestYaml), &man)
+	man, _, errs := ParseWithSourceMap("", manifestYaml)
 	return man, errs
 }
 
+// ParseWithSourceMap parses the manifest and returns the positions of all nodes in it,
+// errors are returned as LocatedError when the position of the offending node can be found
+func ParseWithSourceMap(file string, manifestYaml string) (Manifest, SourceMap, []error) {
+	return parse(file, manifestYaml, nil)
+}
+
+func parse(file string, manifestYaml string, vars *variables) (Manifest, SourceMap, []error) {
+	var man Manifest
+	yml := []byte(manifestYaml)
+	sourceMap := NewSourceMap(file, yml)
+
+	expanded, expandedSourceMap, errs := expandTemplates(file, yml)
+	if len(errs) > 0 {
+		return man, sourceMap, errs
+	}
+	if expanded != nil {
+		yml, sourceMap = expanded, expandedSourceMap
+	}
+
+	errs = unmarshalAsJSON(yml, &man, func(js []byte) ([]byte, []error) {
+		return vars.substitute(js, sourceMap)
+	})
+	for i, err := range errs {
+		errs[i] = sourceMap.locateParseError(file, err)
+	}
+	if len(errs) > 0 {
+		return man, sourceMap, errs
+	}
+
+	// matrix tasks are expanded by the defaulter, so the linters report paths in the expanded manifest
+	return man, sourceMap.withExpandedMatrices(man.Tasks), nil
+}
+
+func (s SourceMap) locateParseError(file string, err error) error {
+	var located LocatedError
+	if errors.As(err, &located) {
+		return err
+	}
+
+	var path string
+	var pathErr pathError
+	for nested := err; errors.As(nested, &pathErr); nested = pathErr.err {
+		path = s.joinNestedPath(path, pathErr.path)
+	}
+	if path != "" {
+		return s.Locate(path, err)
+	}
+
+	if pos := positionFromYAMLError(file, err); !pos.IsZero() {
+		return LocatedError{Position: pos, Err: err}
+	}
+	return err
+}
+
+// joinNestedPath appends the path of a nested error to the path of the error it is wrapped in.
+// The tasks of a parallel or sequence and the pre_promote tasks of a deploy-cf are both decoded as a
+// TaskList, so which of the two a nested "tasks[i]" is in is looked up in the source map
+func (s SourceMap) joinNestedPath(path string, field string) string {
+	if path == "" || field == "" {
+		return path + field
+	}
+
+	nested := path + "." + field
+	if _, found := s[nested]; !found && strings.HasPrefix(field, "tasks[") {
+		prePromote := path + ".pre_promote" + strings.TrimPrefix(field, "tasks")
+		if _, found := s[prePromote]; found {
+			return prePromote
+		}
+	}
+	return nested
+}
+
+func joinPath(path string, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// pathError is an error in the node at path, relative to the node of the pathError it is wrapped in
+type pathError struct {
+	path string
+	err  error
+}
+
+func errorAtPath(path string, err error) error {
+	return pathError{path: path, err: err}
+}
+
+func (e pathError) Error() string {
+	return e.err.Error()
+}
+
+func (e pathError) Unwrap() error {
+	return e.err
+}
+
+// jsonError drops the "json: " prefix from errors of the json decoder and keeps the field they are about
+func jsonError(err error) error {
+	if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+		return errorAtPath(typeErr.Field, errors.New(strings.TrimPrefix(err.Error(), "json: ")))
+	}
+	// the json decoder does not have a type for unknown fields
+	if quoted, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+		field, _ := strconv.Unquote(quoted)
+		return errorAtPath(field, errors.New(strings.TrimPrefix(err.Error(), "json: ")))
+	}
+	return err
+}
+
 // convert YAML to JSON because JSON parser gives more control that we need to unmarshal into tasks
-func unmarshalAsJSON(yml []byte, out *Manifest) []error {
+func unmarshalAsJSON(yml []byte, out *Manifest, substitute func(js []byte) ([]byte, []error)) []error {
 	js, err := yaml.YAMLToJSONStrict(yml)
 	if err != nil {
 		if strings.Contains(err.Error(), "already set") {
@@ -25,12 +136,16 @@ func unmarshalAsJSON(yml []byte, out *Manifest) []error {
 		return []error{err}
 	}
 
+	js, errs := substitute(js)
+	if len(errs) > 0 {
+		return errs
+	}
+
 	decoder := json.NewDecoder(bytes.NewReader(js))
 	decoder.DisallowUnknownFields()
 
 	if err := decoder.Decode(out); err != nil {
-		msg := strings.Replace(err.Error(), "json: ", "", -1)
-		return []error{fmt.Errorf(msg)}
+		return []error{jsonError(err)}
 	}
 	return nil
 }
@@ -43,7 +158,7 @@ func (t *TaskList) UnmarshalJSON(b []byte) error {
 	// first get a raw array
 	var rawTasks []json.RawMessage
 	if err := json.Unmarshal(b, &rawTasks); err != nil {
-		return err
+		return jsonError(err)
 	}
 
 	// then just read the type field
@@ -92,7 +207,7 @@ func (t *TriggerList) UnmarshalJSON(b []byte) error {
 	// first get a raw array
 	var rawTrigger []json.RawMessage
 	if err := json.Unmarshal(b, &rawTrigger); err != nil {
-		return err
+		return jsonError(err)
 	}
 
 	// then just read the type field
@@ -118,79 +233,35 @@ func (t *TriggerList) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func unmarshalTask(taskIndex int, rawTask json.RawMessage, taskType string) (task Task, err error) {
-	decoder := json.NewDecoder(bytes.NewReader(rawTask))
-	decoder.DisallowUnknownFields()
+var errUnknownTaskType = errors.New("unknown task type")
 
-	unmarshal := func(t Task) error {
-		if jsonErr := decoder.Decode(t); jsonErr != nil {
-			return fmt.Errorf("tasks[%v] : %w", taskIndex, jsonErr)
-		}
-		return nil
+func unmarshalTask(taskIndex int, rawTask json.RawMessage, taskType string) (task Task, err error) {
+	if matrix, isMatrix, matrixErr := unmarshalMatrix(rawTask, taskType); isMatrix {
+		task, err = matrix, matrixErr
+	} else {
+		task, err = decodeTask(rawTask, taskType)
 	}
 
-	// unmarshal into the correct type of Task
-	switch taskType {
-	case "run":
-		t := Run{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "deploy-cf":
-		t := DeployCF{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "deploy-katee":
-		t := DeployKatee{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "docker-push":
-		t := DockerPush{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "docker-compose":
-		t := DockerCompose{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "consumer-integration-test":
-		t := ConsumerIntegrationTest{}
-		err = unmarshal(&t)
-		//default use_covenant to true
-		if !strings.Contains(string(rawTask), `"use_covenant"`) {
-			t.UseCovenant = true
-		}
-		t.Type = ""
-		task = t
-	case "deploy-ml-zip":
-		t := DeployMLZip{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "deploy-ml-modules":
-		t := DeployMLModules{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "parallel":
-		t := Parallel{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
-	case "sequence":
-		t := Sequence{}
-		err = unmarshal(&t)
-		t.Type = ""
-		task = t
+	if errors.Is(err, errUnknownTaskType) {
+		err = errorAtPath("type", fmt.Errorf("tasks[%v] unknown type '%s'. Must be one of %s", taskIndex, taskType, taskTypeNames()))
+		return task, errorAtPath(fmt.Sprintf("tasks[%v]", taskIndex), err)
+	}
+	if err != nil {
+		return task, errorAtPath(fmt.Sprintf("tasks[%v]", taskIndex), fmt.Errorf("tasks[%v] : %w", taskIndex, err))
+	}
+	return task, nil
+}
 
-	default:
-		err = fmt.Errorf("tasks[%v] unknown type '%s'. Must be one of 'run', 'docker-compose', 'deploy-cf', 'docker-push', 'consumer-integration-test', 'parallel', 'sequence'", taskIndex, taskType)
+func decodeTask(rawTask json.RawMessage, taskType string) (task Task, err error) {
+	definition, found := LookupTask(taskType)
+	if !found {
+		return nil, errUnknownTaskType
 	}
 
-	return task, err
+	if definition.Decode != nil {
+		return definition.Decode(rawTask)
+	}
+	return DecodeTask(rawTask, definition.Task)
 }
 
 func unmarshalTrigger(triggerIndex int, rawTrigger json.RawMessage, triggerType string) (trigger Trigger, err error) {
@@ -199,7 +270,7 @@ func unmarshalTrigger(triggerIndex int, rawTrigger json.RawMessage, triggerType
 
 	unmarshal := func(t Trigger) error {
 		if jsonErr := decoder.Decode(t); jsonErr != nil {
-			return fmt.Errorf("triggers.trigger[%v] : %w", triggerIndex, jsonErr)
+			return errorAtPath(fmt.Sprintf("triggers[%v]", triggerIndex), fmt.Errorf("triggers.trigger[%v] : %w", triggerIndex, jsonError(jsonErr)))
 		}
 		return nil
 	}
@@ -226,8 +297,19 @@ func unmarshalTrigger(triggerIndex int, rawTrigger json.RawMessage, triggerType
 		err = unmarshal(&t)
 		t.Type = ""
 		trigger = t
+	case "pull_request":
+		t := PullRequestTrigger{}
+		err = unmarshal(&t)
+		t.Type = ""
+		trigger = t
+	case "tag":
+		t := TagTrigger{}
+		err = unmarshal(&t)
+		t.Type = ""
+		trigger = t
 	default:
-		err = fmt.Errorf("triggers[%v] unknown type '%s'. Must be one of 'git', 'cron', 'docker', 'pipeline'", triggerIndex, triggerType)
+		err = errorAtPath("type", fmt.Errorf("triggers[%v] unknown type '%s'. Must be one of 'git', 'cron', 'docker', 'pipeline', 'pull_request', 'tag'", triggerIndex, triggerType))
+		err = errorAtPath(fmt.Sprintf("triggers[%v]", triggerIndex), err)
 	}
 
 	return trigger, err
@@ -499,6 +499,42 @@ triggers:
 	assert.Equal(t, GitTrigger{ShallowDefined: true}, man.Triggers[0])
 }
 
+func TestPullRequestTrigger(t *testing.T) {
+	yaml := `
+triggers:
+- type: pull_request
+  branches: [main]
+  paths: [src/]
+  types: [opened, synchronize]
+tasks:
+- type: run
+  skip_on_pull_request: true
+- type: run
+- type: deploy-cf
+- type: docker-push
+  skip_on_pull_request: false
+`
+	man, errs := Parse(yaml)
+	assert.Empty(t, errs)
+	assert.Equal(t, PullRequestTrigger{Branches: []string{"main"}, Paths: []string{"src/"}, Types: []string{"opened", "synchronize"}}, man.Triggers[0])
+	assert.True(t, man.Tasks[0].SkipsOnPullRequest())
+	assert.False(t, man.Tasks[1].SkipsOnPullRequest())
+	assert.True(t, man.Tasks[2].SkipsOnPullRequest())
+	assert.False(t, man.Tasks[3].SkipsOnPullRequest())
+}
+
+func TestTagTrigger(t *testing.T) {
+	man, errs := Parse(`
+triggers:
+- type: tag
+  pattern: v*
+  semver: true
+`)
+	assert.Empty(t, errs)
+	assert.Equal(t, TagTrigger{Pattern: "v*", Semver: true}, man.Triggers[0])
+	assert.Equal(t, "v", man.Triggers.GetTagTrigger().VersionPrefix())
+}
+
 func TestDockerComposeIsSplitIntoArray(t *testing.T) {
 	yaml := `
 team: my team
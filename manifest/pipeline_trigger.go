@@ -15,6 +15,7 @@ type PipelineTrigger struct {
 	Pipeline     string `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
 	Job          string `json:"job,omitempty" yaml:"job,omitempty"`
 	Status       string `json:"status,omitempty" yaml:"status,omitempty"`
+	Repository   string `json:"repository,omitempty" yaml:"repository,omitempty"`
 }
 
 func (p PipelineTrigger) GetTriggerAttempts() int {
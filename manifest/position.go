@@ -0,0 +1,144 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a location in a halfpipe manifest
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) IsZero() bool {
+	return p.Line == 0
+}
+
+// String formats the position as file:line:col so editors and CI annotations can jump to it
+func (p Position) String() string {
+	var parts []string
+	if p.File != "" {
+		parts = append(parts, p.File)
+	}
+	if p.Line > 0 {
+		parts = append(parts, fmt.Sprint(p.Line))
+		if p.Column > 0 {
+			parts = append(parts, fmt.Sprint(p.Column))
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// SourceMap maps paths in the manifest, like "tasks[3].pre_promote[1]" or "triggers[0].uri",
+// to the position of the node in the yaml it was parsed from
+type SourceMap map[string]Position
+
+func NewSourceMap(file string, manifestYaml []byte) SourceMap {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(manifestYaml, &doc); err != nil || len(doc.Content) == 0 {
+		return SourceMap{}
+	}
+
+	sm := SourceMap{}
+	sm.walk(file, "", doc.Content[0])
+	return sm
+}
+
+func (s SourceMap) walk(file string, path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				// merge key, the fields of the anchor are fields of this node
+				s.walk(file, path, value)
+				continue
+			}
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			s.add(keyPath, Position{File: file, Line: key.Line, Column: key.Column})
+			s.walk(file, keyPath, value)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			s.add(itemPath, Position{File: file, Line: item.Line, Column: item.Column})
+			s.walk(file, itemPath, item)
+		}
+	case yaml.AliasNode:
+		s.walk(file, path, node.Alias)
+	}
+}
+
+func (s SourceMap) add(path string, pos Position) {
+	if _, found := s[path]; !found {
+		s[path] = pos
+	}
+}
+
+// Lookup returns the position of the path, or of the closest parent of the path that is in the source map.
+// Nested task lists as reported by the linters, "tasks[0][1]", are resolved to "tasks[0].tasks[1]".
+func (s SourceMap) Lookup(path string) (Position, bool) {
+	path = strings.ReplaceAll(path, "][", "].tasks[")
+	for path != "" {
+		if pos, found := s[path]; found {
+			return pos, true
+		}
+		path = parentPath(path)
+	}
+	return Position{}, false
+}
+
+// Locate annotates err with the path and the position of the path in the manifest
+func (s SourceMap) Locate(path string, err error) error {
+	pos, _ := s.Lookup(path)
+	return LocatedError{Path: path, Position: pos, Err: err}
+}
+
+func parentPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		return path[:strings.LastIndex(path, "[")]
+	}
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// LocatedError is an error that knows which node in the halfpipe manifest caused it
+type LocatedError struct {
+	Path     string
+	Position Position
+	Err      error
+}
+
+func (e LocatedError) Error() string {
+	if e.Position.IsZero() {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Position, e.Err)
+}
+
+func (e LocatedError) Unwrap() error {
+	return e.Err
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+):`)
+
+// positionFromYAMLError finds the line number in errors from the yaml parser, e.g. "yaml: line 3: did not find expected key".
+// The yaml parser only reports the position of syntax errors and duplicate keys in the message, and there is no
+// source map for a document that does not parse
+func positionFromYAMLError(file string, err error) (pos Position) {
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		_, _ = fmt.Sscan(m[1], &pos.Line)
+		pos.File = file
+	}
+	return pos
+}
@@ -0,0 +1,138 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const positionManifest = `team: my team
+pipeline: my pipeline
+triggers:
+- type: git
+  watched_paths:
+  - src
+tasks:
+- type: run
+  script: ./build.sh
+  docker:
+    image: alpine
+- type: parallel
+  tasks:
+  - type: run
+    script: ./test.sh
+- type: deploy-cf
+  pre_promote:
+  - type: run
+    script: ./smoke.sh
+`
+
+func TestPositionString(t *testing.T) {
+	assert.Equal(t, ".halfpipe.io:3:5", Position{File: ".halfpipe.io", Line: 3, Column: 5}.String())
+	assert.Equal(t, ".halfpipe.io:3", Position{File: ".halfpipe.io", Line: 3}.String())
+	assert.Equal(t, "3:5", Position{Line: 3, Column: 5}.String())
+	assert.Equal(t, "", Position{}.String())
+}
+
+func TestSourceMapLookup(t *testing.T) {
+	sm := NewSourceMap(".halfpipe.io", []byte(positionManifest))
+
+	tests := map[string]Position{
+		"team":                           {File: ".halfpipe.io", Line: 1, Column: 1},
+		"triggers[0].watched_paths[0]":   {File: ".halfpipe.io", Line: 6, Column: 5},
+		"tasks[0]":                       {File: ".halfpipe.io", Line: 8, Column: 3},
+		"tasks[0].docker.image":          {File: ".halfpipe.io", Line: 11, Column: 5},
+		"tasks[0].docker.image.unknown":  {File: ".halfpipe.io", Line: 11, Column: 5},
+		"tasks[1][0]":                    {File: ".halfpipe.io", Line: 14, Column: 5},
+		"tasks[1].tasks[0].script":       {File: ".halfpipe.io", Line: 15, Column: 5},
+		"tasks[2].pre_promote[0].script": {File: ".halfpipe.io", Line: 19, Column: 5},
+		"tasks[2].pre_promote[1].script": {File: ".halfpipe.io", Line: 17, Column: 3},
+		"tasks[5]":                       {File: ".halfpipe.io", Line: 7, Column: 1},
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			pos, found := sm.Lookup(path)
+			assert.True(t, found)
+			assert.Equal(t, expected, pos)
+		})
+	}
+
+	_, found := sm.Lookup("notifications")
+	assert.False(t, found)
+}
+
+func TestLocatedError(t *testing.T) {
+	err := errors.New("blah")
+	sm := NewSourceMap(".halfpipe.io", []byte(positionManifest))
+
+	located := sm.Locate("pipeline", err)
+	assert.Equal(t, ".halfpipe.io:2:1: blah", located.Error())
+	assert.ErrorIs(t, located, err)
+
+	assert.Equal(t, "blah", sm.Locate("notifications", err).Error())
+}
+
+func TestParseErrorsHavePositions(t *testing.T) {
+	tests := map[string]struct {
+		yaml     string
+		expected string
+	}{
+		"invalid yaml": {
+			yaml:     "team: a\n  pipeline: b\n",
+			expected: ".halfpipe.io:2",
+		},
+		"duplicate key": {
+			yaml:     "team: a\npipeline: b\nteam: c\n",
+			expected: ".halfpipe.io:3",
+		},
+		"unknown top level field": {
+			yaml:     "team: a\nblah: b\n",
+			expected: ".halfpipe.io:2:1",
+		},
+		"unknown task type": {
+			yaml:     "tasks:\n- type: run\n- type: blah\n",
+			expected: ".halfpipe.io:3:3",
+		},
+		"unknown field in task": {
+			yaml:     "tasks:\n- type: run\n  scriptz: ./a.sh\n",
+			expected: ".halfpipe.io:3:3",
+		},
+		"wrong type in task": {
+			yaml:     "tasks:\n- type: run\n  docker:\n    image: [a]\n",
+			expected: ".halfpipe.io:4:5",
+		},
+		"unknown field in nested task": {
+			yaml:     "tasks:\n- type: parallel\n  tasks:\n  - type: run\n    blah: true\n",
+			expected: ".halfpipe.io:5:5",
+		},
+		"unknown field in pre promote": {
+			yaml:     "tasks:\n- type: deploy-cf\n  pre_promote:\n  - type: run\n    blah: true\n",
+			expected: ".halfpipe.io:5:5",
+		},
+		"unknown task type in pre promote": {
+			yaml:     "tasks:\n- type: deploy-cf\n  pre_promote:\n  - type: run\n  - type: blah\n",
+			expected: ".halfpipe.io:5:5",
+		},
+		"unknown trigger type": {
+			yaml:     "triggers:\n- type: git\n- type: blah\n",
+			expected: ".halfpipe.io:3:3",
+		},
+		"unknown field in trigger": {
+			yaml:     "triggers:\n- type: git\n  blah: true\n",
+			expected: ".halfpipe.io:3:3",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, errs := ParseWithSourceMap(".halfpipe.io", test.yaml)
+			if assert.Len(t, errs, 1) {
+				var located LocatedError
+				assert.ErrorAs(t, errs[0], &located)
+				assert.Equal(t, test.expected, located.Position.String())
+			}
+		})
+	}
+}
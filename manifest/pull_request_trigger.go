@@ -0,0 +1,21 @@
+package manifest
+
+type PullRequestTrigger struct {
+	Type     string
+	Branches []string `json:"branches,omitempty" yaml:"branches,omitempty"`
+	Paths    []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Types    []string `json:"types,omitempty" yaml:"types,omitempty"`
+}
+
+func (t PullRequestTrigger) GetTriggerAttempts() int {
+	return 1
+}
+
+func (t PullRequestTrigger) MarshalYAML() (interface{}, error) {
+	t.Type = "pull_request"
+	return t, nil
+}
+
+func (PullRequestTrigger) GetTriggerName() string {
+	return "pull_request"
+}
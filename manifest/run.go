@@ -11,22 +11,36 @@ type Docker struct {
 	Password string `yaml:"password,omitempty" secretAllowed:"true"`
 }
 
+// Cache is a list of paths in the repo that are kept between runs of a task.
+// In GitHub Actions the cache is restored from the last run with the same key files,
+// in Concourse the paths are links to the halfpipe cache of the task.
+type Cache struct {
+	Paths    []string `yaml:"paths,omitempty"`
+	KeyFiles []string `json:"key_files,omitempty" yaml:"key_files,omitempty"`
+}
+
 type Run struct {
 	Type                   string
-	Name                   string        `yaml:"name,omitempty"`
-	ManualTrigger          bool          `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
-	Script                 string        `yaml:"script,omitempty"`
-	Docker                 Docker        `yaml:"docker,omitempty"`
-	Privileged             bool          `yaml:"privileged,omitempty"`
-	Vars                   Vars          `yaml:"vars,omitempty" secretAllowed:"true"`
-	SaveArtifacts          []string      `json:"save_artifacts" yaml:"save_artifacts,omitempty"`
-	RestoreArtifacts       bool          `json:"restore_artifacts" yaml:"restore_artifacts,omitempty"`
-	SaveArtifactsOnFailure []string      `json:"save_artifacts_on_failure" yaml:"save_artifacts_on_failure,omitempty"`
-	Retries                int           `yaml:"retries,omitempty"`
-	NotifyOnSuccess        bool          `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
-	Notifications          Notifications `json:"notifications,omitempty" yaml:"notifications,omitempty"`
-	Timeout                string        `json:"timeout,omitempty" yaml:"timeout,omitempty"`
-	BuildHistory           int           `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	Name                   string            `yaml:"name,omitempty"`
+	ManualTrigger          bool              `json:"manual_trigger" yaml:"manual_trigger,omitempty"`
+	Script                 string            `yaml:"script,omitempty"`
+	Docker                 Docker            `yaml:"docker,omitempty"`
+	Container              Docker            `yaml:"container,omitempty"`
+	Privileged             bool              `yaml:"privileged,omitempty"`
+	Vars                   Vars              `yaml:"vars,omitempty" secretAllowed:"true"`
+	Cache                  Cache             `json:"cache,omitempty" yaml:"cache,omitempty"`
+	Outputs                map[string]string `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	SaveArtifacts          []string          `json:"save_artifacts" yaml:"save_artifacts,omitempty"`
+	RestoreArtifacts       bool              `json:"restore_artifacts" yaml:"restore_artifacts,omitempty"`
+	SaveArtifactsOnFailure []string          `json:"save_artifacts_on_failure" yaml:"save_artifacts_on_failure,omitempty"`
+	Retries                int               `yaml:"retries,omitempty"`
+	NotifyOnSuccess        bool              `json:"notify_on_success,omitempty" yaml:"notify_on_success,omitempty"`
+	Notifications          Notifications     `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	Timeout                string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	BuildHistory           int               `json:"build_history,omitempty" yaml:"build_history,omitempty"`
+	SkipOnPullRequest      bool              `json:"skip_on_pull_request,omitempty" yaml:"skip_on_pull_request,omitempty"`
+	RunsOn                 []string          `json:"runs_on,omitempty" yaml:"runs_on,omitempty"`
+	WatchedPaths           []string          `json:"watched_paths,omitempty" yaml:"watched_paths,omitempty"`
 }
 
 func (r Run) GetSecrets() map[string]string {
@@ -94,6 +108,18 @@ func (r Run) IsManualTrigger() bool {
 	return r.ManualTrigger
 }
 
+func (r Run) SkipsOnPullRequest() bool {
+	return r.SkipOnPullRequest
+}
+
+func (r Run) GetRunsOn() []string {
+	return r.RunsOn
+}
+
+func (r Run) GetWatchedPaths() []string {
+	return r.WatchedPaths
+}
+
 func (r Run) SavesArtifacts() bool {
 	return len(r.SaveArtifacts) > 0
 }
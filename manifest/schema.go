@@ -0,0 +1,341 @@
+package manifest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// schemaTrigger is a trigger that can be used in the manifest
+type schemaTrigger struct {
+	Type        string
+	Value       any
+	Description string
+}
+
+var schemaTriggers = []schemaTrigger{
+	{"git", GitTrigger{}, "Triggers the pipeline on commits to the git repo"},
+	{"timer", TimerTrigger{}, "Triggers the pipeline on a cron schedule"},
+	{"docker", DockerTrigger{}, "Triggers the pipeline when a docker image is updated"},
+	{"pipeline", PipelineTrigger{}, "Triggers the pipeline when a job in another pipeline succeeds"},
+	{"pull_request", PullRequestTrigger{}, "Triggers the workflow on pull requests, only supported in GitHub Actions"},
+	{"tag", TagTrigger{}, "Triggers the pipeline on git tags instead of commits to the branch, BUILD_VERSION is the name of the tag"},
+}
+
+// schemaDescriptions are the descriptions of the fields in the manifest.
+// A description for '<type>.<field>' takes precedence over the one for '<field>'.
+var schemaDescriptions = map[string]string{
+	"team":                  "Name of the team that owns the pipeline, used to look up secrets",
+	"pipeline":              "Name of the pipeline",
+	"slack_channel":         "Deprecated, use 'notifications'. Slack channel to notify on failure",
+	"teams_webhook":         "Deprecated, use 'notifications'. Microsoft Teams webhook to notify on failure",
+	"slack_success_message": "Deprecated, use 'notifications'. Message sent to slack on success",
+	"slack_failure_message": "Deprecated, use 'notifications'. Message sent to slack on failure",
+	"artifact_config":       "Bucket used to store artifacts instead of the default one",
+	"bucket":                "Name of the GCS bucket",
+	"json_key":              "GCP service account key with access to the bucket",
+	"feature_toggles":       "Opt in to features",
+	"triggers":              "What triggers the pipeline",
+	"tasks":                 "The tasks of the pipeline, run in sequence",
+	"platform":              "CI platform to render the pipeline for",
+	"templates":             "Named partial tasks that tasks can inherit fields from with 'extends'",
+	"vars_files":            "YAML files, relative to the manifest, with the vars that '${vars.<name>}' in the manifest is replaced with",
+	"include":               "YAML files, relative to the manifest, with fields that are added to the manifest unless it sets them itself",
+	"dispatch_job_status":   "Repositories, as 'owner/name', the jobs in GitHub Actions dispatch their status to, so that pipeline triggers in them can trigger on the jobs",
+	"extends":               "Name of the template in 'templates' to inherit fields from",
+	"matrix":                "Runs the task once for every entry in 'include'",
+	"matrix.mode":           "Run the tasks in 'parallel' (default) or in 'sequence', pre_promote tasks always run in sequence",
+	"matrix.include":        "Each entry overrides fields of the task, maps such as 'vars' are merged",
+
+	"notifications":      "Where to send notifications",
+	"on_success":         "Deprecated, use 'success'. Slack channels to notify on success",
+	"on_success_message": "Deprecated, use 'success'. Message sent on success",
+	"on_failure":         "Deprecated, use 'failure'. Slack channels to notify on failure",
+	"on_failure_message": "Deprecated, use 'failure'. Message sent on failure",
+	"success":            "Channels to notify on success",
+	"failure":            "Channels to notify on failure",
+	"slack":              "Slack channel",
+	"teams":              "Microsoft Teams webhook",
+	"message":            "Custom message",
+
+	"type":                      "Type of the task",
+	"name":                      "Name of the task, must be unique in the pipeline",
+	"manual_trigger":            "Only run the task when it is triggered manually, in GitHub Actions it waits for the reviewers of the environment with the name of the task",
+	"script":                    "Script to run, relative to the manifest",
+	"docker":                    "Docker image to run the script in",
+	"image":                     "Docker image",
+	"username":                  "Username used to authenticate, can be a secret",
+	"password":                  "Password used to authenticate, can be a secret",
+	"privileged":                "Run the container in privileged mode",
+	"vars":                      "Environment variables, values can be secrets",
+	"secrets":                   "Secrets made available to the docker build as build secrets",
+	"save_artifacts":            "Files or directories to save for later tasks",
+	"restore_artifacts":         "Restore the artifacts saved by previous tasks",
+	"save_artifacts_on_failure": "Files or directories to save when the task fails",
+	"retries":                   "Number of times to retry the task when it fails",
+	"notify_on_success":         "Deprecated, use 'notifications.success'",
+	"timeout":                   "Timeout of the task, e.g. '1h30m'",
+	"build_history":             "Number of builds to keep",
+	"outputs":                   "Values later tasks can use in 'vars' as '${{ outputs.<task>.<key> }}', key to the file, relative to the manifest, the value is read from",
+	"cache":                     "Paths in the repo that are kept between runs of the task",
+	"paths":                     "Directories to cache, relative to the manifest, e.g. '.gradle/caches'",
+	"key_files":                 "Files, relative to the manifest, the cache is keyed on in GitHub Actions, e.g. 'build.gradle'",
+	"skip_on_pull_request":      "Do not run the task on pull requests, defaults to true for docker-push and deploy tasks",
+	"runs_on":                   "Labels of the GitHub Actions runner to run the task on",
+	"container":                 "Docker image the GitHub Actions job runs in, the script is run directly in it",
+	"api":                       "Cloud Foundry API",
+	"space":                     "Cloud Foundry space",
+	"org":                       "Cloud Foundry org",
+	"manifest":                  "Path to the Cloud Foundry manifest",
+	"test_domain":               "Domain used for the candidate app route",
+	"deploy_artifact":           "Artifact to deploy instead of the build path",
+	"pre_promote":               "Tasks to run against the candidate app before it is promoted",
+	"pre_start":                 "cf commands to run before the app is started",
+	"rolling":                   "Use a rolling deploy instead of blue/green",
+	"cli_version":               "Version of the cf cli",
+	"docker_tag":                "Tag of the docker image to deploy",
+	"sso_route":                 "Route of the app behind SSO",
+	"ignore_vulnerabilities":    "Do not fail the task when the image has vulnerabilities",
+	"scan_timeout":              "Timeout in minutes for the vulnerability scan",
+	"scan":                      "Vulnerability scan of the image before it is pushed",
+	"severity":                  "Lowest severity of vulnerabilities the scan reports, one of UNKNOWN, LOW, MEDIUM, HIGH or CRITICAL. Defaults to CRITICAL",
+	"ignore_unfixed":            "Ignore vulnerabilities that have no fix yet. Defaults to true",
+	"mode":                      "'fail' to not push the image when it has vulnerabilities, 'warn' to only report them. Defaults to 'fail', or 'warn' with ignore_vulnerabilities",
+	"ignore_file":               "Path to the file with the vulnerabilities to ignore, relative to the manifest. Defaults to .trivyignore",
+	"report":                    "Save a report of the scan in the format 'sarif' or 'json' as an artifact",
+	"sbom":                      "Attach an SBOM of the image in the format 'spdx' or 'cyclonedx' as a signed attestation",
+	"sign":                      "Sign the image with cosign, keyless on GitHub Actions unless signing_key is set",
+	"signing_key":               "Secret with the cosign private key to sign the image and the SBOM with",
+	"signing_key_password":      "Secret with the password of the cosign private key",
+	"dockerfile_path":           "Path to the Dockerfile",
+	"build_path":                "Path used as the docker build context",
+	"target":                    "Stage of a multi-stage Dockerfile to build",
+	"bake_file":                 "docker-bake.hcl file, relative to the manifest, the images are built with instead of dockerfile_path and build_path, target is the bake target. The build args are set as args of the target",
+	"images":                    "Images to build and push in the same job instead of image, e.g. the stages of a multi-stage Dockerfile",
+	"tag":                       "Deprecated",
+	"tags":                      "Tags to push the image with, defaults to {gitref}, {version} and latest. {major} and {minor} are the first one and two parts of the version, {branch} is the branch of the git trigger",
+	"platforms":                 "Platforms to build the image for",
+	"use_cache":                 "Use the registry as a build cache",
+	"command":                   "Command to run in the service",
+	"service":                   "Name of the docker-compose service to run",
+	"compose_file":              "Space separated list of docker-compose files",
+	"vela_manifest":             "Path to the vela manifest",
+	"environment":               "Katee environment",
+	"namespace":                 "Katee namespace",
+	"deployment_check_timeout":  "Seconds to wait for the deployment to become healthy",
+	"platform_version":          "Version of the Katee platform",
+	"consumer":                  "Consumer repo and path, e.g. 'repo/path'",
+	"consumer_host":             "Host of the consumer",
+	"git_clone_options":         "Options passed to git clone of the consumer repo",
+	"provider_host":             "Host of the provider",
+	"provider_name":             "Name of the provider",
+	"docker_compose_file":       "docker-compose file used to run the tests",
+	"docker_compose_service":    "docker-compose service used to run the tests",
+	"use_covenant":              "Use covenant to run the tests",
+	"ml_modules_version":        "Version of ml-modules to deploy",
+	"app_name":                  "Name of the MarkLogic app",
+	"app_version":               "Version of the MarkLogic app",
+	"targets":                   "MarkLogic hosts to deploy to",
+	"use_build_version":         "Use the build version as the app version",
+	"deploy_zip":                "Path to the zip to deploy",
+	"parallel.tasks":            "Tasks to run in parallel",
+	"sequence.tasks":            "Tasks to run in sequence",
+
+	"uri":                        "URI of the git repo",
+	"private_key":                "Private key used to clone the git repo",
+	"watched_paths":              "Only trigger on changes to these paths",
+	"ignored_paths":              "Do not trigger on changes to these paths",
+	"git_crypt_key":              "Key used to unlock git-crypt",
+	"branch":                     "Branch that triggers the pipeline",
+	"shallow":                    "Do a shallow clone",
+	"git.manual_trigger":         "Do not trigger the pipeline automatically on commits",
+	"cron":                       "Cron expression",
+	"docker.image":               "Docker image that triggers the pipeline",
+	"concourse_url":              "URL of Concourse",
+	"pipeline.team":              "Team of the upstream pipeline",
+	"pipeline.pipeline":          "Name of the upstream pipeline",
+	"job":                        "Job in the upstream pipeline, in GitHub Actions it can be empty to trigger on the whole upstream workflow in the same repo",
+	"status":                     "Status of the job that triggers the pipeline",
+	"pipeline.repository":        "Repository of the upstream workflow in GitHub Actions as 'owner/name', defaults to the repo of the git trigger. The upstream pipeline must dispatch its job statuses to this repo with 'dispatch_job_status'",
+	"docker.username":            "Username for the registry of the image",
+	"docker.password":            "Password for the registry of the image",
+	"pull_request.branches":      "Base branches of the pull requests, defaults to the branch of the git trigger",
+	"pull_request.paths":         "Only trigger on pull requests that change these paths, defaults to the paths of the git trigger",
+	"tag.pattern":                "Glob pattern of the tags that trigger the pipeline, defaults to '*'",
+	"tag.semver":                 "Only trigger on tags that are a semantic version 'MAJOR.MINOR.PATCH' after the prefix of the pattern, e.g. 'v1.2.3' for 'v*'",
+	"pull_request.types":         "Activity types of the pull requests, defaults to 'opened', 'synchronize' and 'reopened'",
+	"deploy-katee.tag":           "Tag of the docker image to deploy, 'version' or 'gitref'",
+	"deploy-cf.username":         "Cloud Foundry username",
+	"deploy-cf.password":         "Cloud Foundry password",
+	"docker-push.image":          "Image to push, images in the registries of HALFPIPE_DOCKER_REGISTRIES need no credentials, for other registries set username and password",
+	"docker-push.username":       "Username to log in to the registry of the image, not needed for the registries of HALFPIPE_DOCKER_REGISTRIES",
+	"docker-push.password":       "Password to log in to the registry of the image, not needed for the registries of HALFPIPE_DOCKER_REGISTRIES",
+	"deploy-ml-zip.username":     "MarkLogic username",
+	"deploy-ml-zip.password":     "MarkLogic password",
+	"deploy-ml-modules.username": "MarkLogic username",
+	"deploy-ml-modules.password": "MarkLogic password",
+}
+
+// JSONSchema generates a JSON Schema (draft 2020-12) for the halfpipe manifest from the manifest types
+func JSONSchema() ([]byte, error) {
+	defs := map[string]any{}
+
+	var tasks []any
+	for _, t := range TaskDefinitions() {
+		def := schemaForStruct(reflect.TypeOf(t.Task), t.Type)
+		def["description"] = t.Description
+		properties := def["properties"].(map[string]any)
+		properties["type"] = map[string]any{"const": t.Type, "description": schemaDescription(t.Type, "type")}
+		if t.Type != "parallel" && t.Type != "sequence" {
+			properties["matrix"] = map[string]any{"$ref": "#/$defs/matrix"}
+		}
+		def["required"] = []string{"type"}
+		defs[t.Type] = def
+		tasks = append(tasks, map[string]any{"$ref": "#/$defs/" + t.Type})
+	}
+
+	var triggers []any
+	for _, t := range schemaTriggers {
+		def := schemaForStruct(reflect.TypeOf(t.Value), t.Type)
+		def["description"] = t.Description
+		def["properties"].(map[string]any)["type"] = map[string]any{"const": t.Type, "description": "Type of the trigger"}
+		def["required"] = []string{"type"}
+		defs[t.Type+"-trigger"] = def
+		triggers = append(triggers, map[string]any{"$ref": "#/$defs/" + t.Type + "-trigger"})
+	}
+
+	defs["task"] = map[string]any{
+		"if":   map[string]any{"required": []string{"extends"}},
+		"then": map[string]any{"type": "object", "properties": map[string]any{"extends": schemaString("extends")}},
+		"else": map[string]any{"oneOf": tasks},
+	}
+	defs["trigger"] = map[string]any{"oneOf": triggers}
+	defs["matrix"] = map[string]any{
+		"type":                 "object",
+		"description":          schemaDescription("", "matrix"),
+		"additionalProperties": false,
+		"required":             []string{"include"},
+		"properties": map[string]any{
+			"mode": map[string]any{
+				"description": schemaDescription("", "matrix.mode"),
+				"enum":        []string{MatrixModeParallel, MatrixModeSequence},
+			},
+			"include": map[string]any{
+				"description": schemaDescription("", "matrix.include"),
+				"type":        "array",
+				"minItems":    1,
+				"items":       map[string]any{"type": "object"},
+			},
+		},
+	}
+
+	schema := schemaForStruct(reflect.TypeOf(Manifest{}), "")
+	schema["properties"].(map[string]any)["templates"] = map[string]any{
+		"description":          schemaDescription("", "templates"),
+		"type":                 "object",
+		"additionalProperties": map[string]any{"type": "object"},
+	}
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "halfpipe manifest"
+	schema["$defs"] = defs
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func schemaDescription(taskType string, field string) string {
+	if definition, found := LookupTask(taskType); found && definition.FieldDescriptions[field] != "" {
+		return definition.FieldDescriptions[field]
+	}
+	if description, found := schemaDescriptions[taskType+"."+field]; found {
+		return description
+	}
+	return schemaDescriptions[field]
+}
+
+func schemaString(field string) map[string]any {
+	return map[string]any{"type": "string", "description": schemaDescription("", field)}
+}
+
+func schemaForStruct(t reflect.Type, taskType string) map[string]any {
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct {
+			// fields of embedded structs are decoded as fields of the struct
+			for name, property := range schemaForStruct(field.Type, taskType)["properties"].(map[string]any) {
+				properties[name] = property
+			}
+			continue
+		}
+
+		name := schemaFieldName(field)
+		if name == "" || field.Name == "Type" {
+			continue
+		}
+
+		property := schemaForType(field.Type, taskType)
+		if description := schemaDescription(taskType, name); description != "" {
+			property["description"] = description
+		}
+		properties[name] = property
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           properties,
+	}
+}
+
+// schemaFieldName is the name used in the manifest, the same as encoding/json uses in the parser
+func schemaFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	for _, tag := range []string{"json", "yaml"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return strings.ToLower(field.Name)
+}
+
+func schemaForType(t reflect.Type, taskType string) map[string]any {
+	switch t {
+	case reflect.TypeOf(Vars{}):
+		return map[string]any{"type": "object", "additionalProperties": map[string]any{"type": []string{"string", "number", "boolean"}}}
+	case reflect.TypeOf(ComposeFiles{}):
+		return map[string]any{"type": "string"}
+	case reflect.TypeOf(TaskList{}):
+		return map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/task"}}
+	case reflect.TypeOf(TriggerList{}):
+		return map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/trigger"}}
+	case reflect.TypeOf(Platform("")):
+		return map[string]any{"enum": []string{"concourse", "actions"}}
+	case reflect.TypeOf(FeatureToggles{}):
+		return map[string]any{"type": "array", "items": map[string]any{"enum": AvailableFeatureToggles}}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int:
+		return map[string]any{"type": "integer"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem(), taskType)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem(), taskType)}
+	case reflect.Struct:
+		return schemaForStruct(t, taskType)
+	case reflect.Pointer:
+		return schemaForType(t.Elem(), taskType)
+	}
+
+	panic("no json schema for type " + t.String())
+}
@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parsedSchema(t *testing.T) map[string]any {
+	t.Helper()
+	bytes, err := JSONSchema()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(bytes, &schema))
+	return schema
+}
+
+func TestSchemaIsDraft202012(t *testing.T) {
+	schema := parsedSchema(t)
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Contains(t, schema["properties"], "tasks")
+	assert.Contains(t, schema["properties"], "triggers")
+	assert.Contains(t, schema["properties"], "templates")
+}
+
+func TestSchemaTasksAreDiscriminatedOnType(t *testing.T) {
+	defs := parsedSchema(t)["$defs"].(map[string]any)
+
+	oneOf := defs["task"].(map[string]any)["else"].(map[string]any)["oneOf"].([]any)
+	assert.Len(t, oneOf, len(TaskDefinitions()))
+	for i, task := range TaskDefinitions() {
+		assert.Equal(t, "#/$defs/"+task.Type, oneOf[i].(map[string]any)["$ref"])
+
+		def := defs[task.Type].(map[string]any)
+		assert.Equal(t, task.Type, def["properties"].(map[string]any)["type"].(map[string]any)["const"])
+		assert.Equal(t, []any{"type"}, def["required"])
+		assert.Equal(t, false, def["additionalProperties"])
+	}
+
+	triggers := defs["trigger"].(map[string]any)["oneOf"].([]any)
+	assert.Len(t, triggers, len(schemaTriggers))
+	for _, trigger := range schemaTriggers {
+		def := defs[trigger.Type+"-trigger"].(map[string]any)
+		assert.Equal(t, trigger.Type, def["properties"].(map[string]any)["type"].(map[string]any)["const"])
+	}
+}
+
+func TestSchemaDescribesAllFields(t *testing.T) {
+	var assertDescribed func(path string, schema map[string]any)
+	assertDescribed = func(path string, schema map[string]any) {
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range properties {
+			property := property.(map[string]any)
+			if _, isRef := property["$ref"]; !isRef {
+				assert.NotEmpty(t, property["description"], "%s.%s has no description", path, name)
+			}
+			assertDescribed(path+"."+name, property)
+			if items, ok := property["items"].(map[string]any); ok {
+				assertDescribed(path+"."+name, items)
+			}
+		}
+	}
+
+	schema := parsedSchema(t)
+	assertDescribed("", schema)
+	for name, def := range schema["$defs"].(map[string]any) {
+		assertDescribed(name, def.(map[string]any))
+	}
+}
@@ -55,36 +55,19 @@ func (s secretValidator) validate(i interface{}, fieldName string, secretTag str
 
 	case reflect.TypeOf(Manifest{}),
 		reflect.TypeOf(Repo{}),
-		reflect.TypeOf(Run{}),
 		reflect.TypeOf(Docker{}),
-		reflect.TypeOf(DockerPush{}),
-		reflect.TypeOf(DockerCompose{}),
-		reflect.TypeOf(DeployCF{}),
-		reflect.TypeOf(DeployKatee{}),
-		reflect.TypeOf(ConsumerIntegrationTest{}),
-		reflect.TypeOf(DeployMLZip{}),
-		reflect.TypeOf(DeployMLModules{}),
+		reflect.TypeOf(Cache{}),
+		reflect.TypeOf(Scan{}),
+		reflect.TypeOf(DockerImage{}),
 		reflect.TypeOf(ArtifactConfig{}),
 		reflect.TypeOf(GitTrigger{}),
 		reflect.TypeOf(TimerTrigger{}),
 		reflect.TypeOf(DockerTrigger{}),
-		reflect.TypeOf(PipelineTrigger{}):
-
-		for i := 0; i < v.NumField(); i++ {
-			field := v.Field(i)
-			name := v.Type().Field(i).Name
-			jsonTag := v.Type().Field(i).Tag.Get("json")
-			secretTag := v.Type().Field(i).Tag.Get(tagName)
-
-			var realFieldName string
-			if fieldName == "" {
-				realFieldName = s.getRealFieldName(name, jsonTag)
-			} else {
-				realFieldName = fmt.Sprintf("%s.%s", fieldName, s.getRealFieldName(name, jsonTag))
-			}
+		reflect.TypeOf(PipelineTrigger{}),
+		reflect.TypeOf(PullRequestTrigger{}),
+		reflect.TypeOf(TagTrigger{}):
 
-			s.validate(field.Interface(), realFieldName, secretTag, errs, platform)
-		}
+		s.validateFields(v, fieldName, errs, platform)
 
 	case reflect.TypeOf(TaskList{}):
 		for i, elem := range v.Interface().(TaskList) {
@@ -112,12 +95,23 @@ func (s secretValidator) validate(i interface{}, fieldName string, secretTag str
 			s.validate(elem, realFieldName, secretTag, errs, platform)
 		}
 
+	case reflect.TypeOf([]DockerImage{}):
+		for i, elem := range v.Interface().([]DockerImage) {
+			realFieldName := fmt.Sprintf("%s[%d]", fieldName, i)
+			s.validate(elem, realFieldName, secretTag, errs, platform)
+		}
+
 	case reflect.TypeOf(FeatureToggles{}):
 		for i, elem := range v.Interface().(FeatureToggles) {
 			realFieldName := fmt.Sprintf("%s[%d]", fieldName, i)
 			s.validate(elem, realFieldName, secretTag, errs, platform)
 		}
 
+	case reflect.TypeOf(map[string]string{}):
+		for key, value := range v.Interface().(map[string]string) {
+			s.validate(value, fmt.Sprintf("%s[%s]", fieldName, key), secretTag, errs, platform)
+		}
+
 	case reflect.TypeOf(Vars{}):
 		for key, value := range v.Interface().(Vars) {
 			realKeyName := fmt.Sprintf("key %s[%s]", fieldName, key)
@@ -159,7 +153,7 @@ func (s secretValidator) validate(i interface{}, fieldName string, secretTag str
 			}
 		}
 
-	case reflect.TypeOf(true), reflect.TypeOf(0), reflect.TypeOf(manifestparser.Application{}):
+	case reflect.TypeOf(true), reflect.TypeOf(new(bool)), reflect.TypeOf(0), reflect.TypeOf(manifestparser.Application{}):
 		// Stuff that we don't care about as they cannot contain secrets.
 		return
 	case reflect.TypeOf(Update{}):
@@ -183,11 +177,38 @@ func (s secretValidator) validate(i interface{}, fieldName string, secretTag str
 		return
 
 	default:
+		// tasks are validated field by field, the fields decide whether they can contain secrets
+		if isRegisteredTask(v.Type()) {
+			s.validateFields(v, fieldName, errs, platform)
+			return
+		}
 		panic(fmt.Sprintf("Not implemented for %s", v.Type()))
 	}
 
 }
 
+func (s secretValidator) validateFields(v reflect.Value, fieldName string, errs *[]error, platform Platform) {
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+
+		field := v.Field(i)
+		name := v.Type().Field(i).Name
+		jsonTag := v.Type().Field(i).Tag.Get("json")
+		secretTag := v.Type().Field(i).Tag.Get(tagName)
+
+		var realFieldName string
+		if fieldName == "" {
+			realFieldName = s.getRealFieldName(name, jsonTag)
+		} else {
+			realFieldName = fmt.Sprintf("%s.%s", fieldName, s.getRealFieldName(name, jsonTag))
+		}
+
+		s.validate(field.Interface(), realFieldName, secretTag, errs, platform)
+	}
+}
+
 func validateMultipleLevelSecret(secret string) bool {
 	// regex matches ((path/to/secret.value)) and ((path/to/more/levels/secret.value))
 	return regexp.MustCompile(`\(\(([a-zA-Z0-9\-_]+\/){1,}[a-zA-Z0-9\-_]+\.[a-zA-Z0-9\-_]+\)\)`).MatchString(secret)
@@ -55,6 +55,18 @@ func (s Sequence) IsManualTrigger() bool {
 	panic("IsManualTrigger should never be used in the rendering for a sequence task as we only care about sub tasks")
 }
 
+func (s Sequence) SkipsOnPullRequest() bool {
+	panic("SkipsOnPullRequest should never be used in the rendering for a sequence task as we only care about sub tasks")
+}
+
+func (s Sequence) GetRunsOn() []string {
+	panic("GetRunsOn should never be used in the rendering for a sequence task as we only care about sub tasks")
+}
+
+func (s Sequence) GetWatchedPaths() []string {
+	panic("GetWatchedPaths should never be used in the rendering for a sequence task as we only care about sub tasks")
+}
+
 func (s Sequence) NotifiesOnSuccess() bool {
 	panic("NotifiesOnSuccess should never be used in the rendering for a sequence task as we only care about sub tasks")
 }
@@ -0,0 +1,27 @@
+package manifest
+
+import "strings"
+
+type TagTrigger struct {
+	Type    string
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Semver  bool   `json:"semver,omitempty" yaml:"semver,omitempty"`
+}
+
+func (t TagTrigger) GetTriggerAttempts() int {
+	return 2
+}
+
+func (t TagTrigger) MarshalYAML() (interface{}, error) {
+	t.Type = "tag"
+	return t, nil
+}
+
+func (TagTrigger) GetTriggerName() string {
+	return "tag"
+}
+
+// VersionPrefix is the part of the pattern in front of the version of semver tags, e.g. 'v' for 'v*'
+func (t TagTrigger) VersionPrefix() string {
+	return strings.TrimSuffix(t.Pattern, "*")
+}
@@ -0,0 +1,126 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TaskDefinition describes a type of task that can be used in the manifest.
+// The defaulter, linters and renderers for the type are registered in their own packages under the same type.
+type TaskDefinition struct {
+	// Type is the value of 'type' in the manifest, e.g. 'run'
+	Type string
+	// Task is the zero value of the struct the task is decoded into
+	Task Task
+	// Description is used in the JSON Schema of the manifest
+	Description string
+	// FieldDescriptions are the descriptions of the fields of the task in the JSON Schema, by their name in the manifest
+	FieldDescriptions map[string]string
+	// Decode decodes the task from json, when not set the fields are decoded into a copy of Task
+	Decode func(rawTask json.RawMessage) (Task, error)
+}
+
+var taskDefinitions []TaskDefinition
+
+// RegisterTask makes a type of task available in the manifest, it panics if the type is already registered
+func RegisterTask(definition TaskDefinition) {
+	if _, found := LookupTask(definition.Type); found {
+		panic(fmt.Sprintf("task type '%s' is already registered", definition.Type))
+	}
+	if reflect.TypeOf(definition.Task).Kind() != reflect.Struct {
+		panic(fmt.Sprintf("task type '%s' must be a struct", definition.Type))
+	}
+	taskDefinitions = append(taskDefinitions, definition)
+}
+
+// TaskDefinitions returns all registered types of task in the order they were registered
+func TaskDefinitions() []TaskDefinition {
+	return append([]TaskDefinition{}, taskDefinitions...)
+}
+
+func LookupTask(taskType string) (TaskDefinition, bool) {
+	for _, definition := range taskDefinitions {
+		if definition.Type == taskType {
+			return definition, true
+		}
+	}
+	return TaskDefinition{}, false
+}
+
+// TaskType returns the registered type of the task, or "" if the task is not of a registered type
+func TaskType(task Task) string {
+	if task == nil {
+		return ""
+	}
+	for _, definition := range taskDefinitions {
+		if reflect.TypeOf(definition.Task) == reflect.TypeOf(task) {
+			return definition.Type
+		}
+	}
+	return ""
+}
+
+func isRegisteredTask(t reflect.Type) bool {
+	for _, definition := range taskDefinitions {
+		if reflect.TypeOf(definition.Task) == t {
+			return true
+		}
+	}
+	return false
+}
+
+func taskTypeNames() string {
+	var names []string
+	for _, definition := range taskDefinitions {
+		names = append(names, fmt.Sprintf("'%s'", definition.Type))
+	}
+	return strings.Join(names, ", ")
+}
+
+// DecodeTask decodes the fields of the task into a new value of the type of task, unknown fields are an error
+func DecodeTask(rawTask json.RawMessage, task Task) (Task, error) {
+	value := reflect.New(reflect.TypeOf(task))
+
+	decoder := json.NewDecoder(bytes.NewReader(rawTask))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(value.Interface())
+
+	// the type is only used to pick the struct, it is not kept in the task
+	if typeField := value.Elem().FieldByName("Type"); typeField.IsValid() && typeField.Kind() == reflect.String {
+		typeField.SetString("")
+	}
+
+	if err != nil {
+		err = jsonError(err)
+	}
+	return value.Elem().Interface().(Task), err
+}
+
+func init() {
+	RegisterTask(TaskDefinition{Type: "run", Task: Run{}, Description: "Runs a script in a docker container"})
+	RegisterTask(TaskDefinition{Type: "docker-compose", Task: DockerCompose{}, Description: "Runs a service from a docker-compose file"})
+	RegisterTask(TaskDefinition{Type: "deploy-cf", Task: DeployCF{}, Description: "Deploys an app to Cloud Foundry"})
+	RegisterTask(TaskDefinition{Type: "deploy-katee", Task: DeployKatee{}, Description: "Deploys an app to Katee"})
+	RegisterTask(TaskDefinition{Type: "docker-push", Task: DockerPush{}, Description: "Builds a docker image and pushes it to a registry"})
+	RegisterTask(TaskDefinition{
+		Type:        "consumer-integration-test",
+		Task:        ConsumerIntegrationTest{},
+		Description: "Runs the consumer's contract tests against this provider",
+		Decode: func(rawTask json.RawMessage) (Task, error) {
+			task, err := DecodeTask(rawTask, ConsumerIntegrationTest{})
+			t := task.(ConsumerIntegrationTest)
+			//default use_covenant to true
+			if !strings.Contains(string(rawTask), `"use_covenant"`) {
+				t.UseCovenant = true
+			}
+			return t, err
+		},
+	})
+	RegisterTask(TaskDefinition{Type: "deploy-ml-zip", Task: DeployMLZip{}, Description: "Deploys a zip to MarkLogic"})
+	RegisterTask(TaskDefinition{Type: "deploy-ml-modules", Task: DeployMLModules{}, Description: "Deploys a version of ml-modules to MarkLogic"})
+	RegisterTask(TaskDefinition{Type: "parallel", Task: Parallel{}, Description: "Runs the sub tasks in parallel"})
+	RegisterTask(TaskDefinition{Type: "sequence", Task: Sequence{}, Description: "Runs the sub tasks in sequence, only allowed inside a parallel"})
+}
@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type deployLambda struct {
+	Run
+	Function string `json:"function,omitempty" yaml:"function,omitempty" secretAllowed:"true"`
+}
+
+func registerDeployLambda(t *testing.T) {
+	registered := taskDefinitions
+	t.Cleanup(func() { taskDefinitions = registered })
+
+	RegisterTask(TaskDefinition{
+		Type:              "deploy-lambda",
+		Task:              deployLambda{},
+		Description:       "Deploys a lambda",
+		FieldDescriptions: map[string]string{"function": "Name of the function"},
+	})
+}
+
+func TestRegisteredTaskCanBeParsed(t *testing.T) {
+	registerDeployLambda(t)
+
+	man, errs := Parse(`tasks:
+- type: deploy-lambda
+  name: deploy
+  function: ((lambda.function))
+`)
+	assert.Empty(t, errs)
+	assert.Equal(t, TaskList{deployLambda{Run: Run{Name: "deploy"}, Function: "((lambda.function))"}}, man.Tasks)
+	assert.Equal(t, "deploy-lambda", TaskType(man.Tasks[0]))
+	assert.Empty(t, NewSecretValidator().Validate(man))
+
+	_, errs = Parse(`tasks:
+- type: deploy-lambda
+  unknown: field
+`)
+	assert.Len(t, errs, 1)
+}
+
+func TestUnknownTaskTypeListsRegisteredTypes(t *testing.T) {
+	registerDeployLambda(t)
+
+	_, errs := Parse(`tasks:
+- type: deploy-lamdba
+`)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "unknown type 'deploy-lamdba'")
+	assert.Contains(t, errs[0].Error(), "'deploy-lambda'")
+}
+
+func TestRegisteredTaskIsInTheSchema(t *testing.T) {
+	registerDeployLambda(t)
+
+	bytes, err := JSONSchema()
+	assert.NoError(t, err)
+
+	var schema struct {
+		Defs map[string]struct {
+			Description string `json:"description"`
+			Properties  map[string]struct {
+				Description string `json:"description"`
+			} `json:"properties"`
+		} `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(bytes, &schema))
+	assert.Equal(t, "Deploys a lambda", schema.Defs["deploy-lambda"].Description)
+	assert.Equal(t, "Name of the function", schema.Defs["deploy-lambda"].Properties["function"].Description)
+	assert.Contains(t, schema.Defs["deploy-lambda"].Properties, "script")
+}
+
+func TestRegisteringATaskTypeTwicePanics(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterTask(TaskDefinition{Type: "run", Task: Run{}})
+	})
+}
@@ -0,0 +1,255 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	templatesKey = "templates"
+	extendsKey   = "extends"
+)
+
+// expandTemplates resolves tasks that extend one of the templates under the top level 'templates' key.
+// A task inherits all fields from the template and only has to set the fields that differ,
+// maps such as 'vars' are merged and all other fields are replaced.
+//
+// The expansion is done on the yaml nodes so the positions of inherited fields still point to the template.
+// If the manifest does not use templates, or it is not valid yaml, nil is returned and the manifest is parsed as is.
+func expandTemplates(file string, manifestYaml []byte) (expanded []byte, sourceMap SourceMap, errs []error) {
+	if _, err := yaml.YAMLToJSONStrict(manifestYaml); err != nil {
+		return nil, nil, nil
+	}
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(manifestYaml, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+		return nil, nil, nil
+	}
+
+	root := doc.Content[0]
+	_, templatesNode := mappingValue(root, templatesKey)
+	if templatesNode == nil && !usesExtends(root) {
+		return nil, nil, nil
+	}
+
+	r := templateResolver{
+		file:      file,
+		templates: map[string]*yamlv3.Node{},
+		resolved:  map[string]*yamlv3.Node{},
+		resolving: map[string]bool{},
+	}
+
+	if templatesNode != nil {
+		if templatesNode.Kind != yamlv3.MappingNode {
+			return nil, nil, []error{r.errorAt(templatesNode, "templates : must be a map of template name to task")}
+		}
+		for i := 0; i+1 < len(templatesNode.Content); i += 2 {
+			r.templates[templatesNode.Content[i].Value] = templatesNode.Content[i+1]
+		}
+		for i := 0; i+1 < len(templatesNode.Content); i += 2 {
+			r.resolveTemplate(templatesNode.Content[i].Value)
+		}
+	}
+
+	updatedRoot := withoutKey(root, templatesKey)
+	if i, tasks := mappingValue(updatedRoot, "tasks"); tasks != nil {
+		updatedRoot.Content[i] = r.resolveTaskList("tasks", tasks)
+	}
+
+	if len(r.errs) > 0 {
+		return nil, nil, r.errs
+	}
+
+	doc.Content[0] = updatedRoot
+	expanded, err := yamlv3.Marshal(&doc)
+	if err != nil {
+		return nil, nil, []error{err}
+	}
+
+	sourceMap = SourceMap{}
+	sourceMap.walk(file, "", updatedRoot)
+	return expanded, sourceMap, nil
+}
+
+type templateResolver struct {
+	file      string
+	templates map[string]*yamlv3.Node
+	resolved  map[string]*yamlv3.Node
+	resolving map[string]bool
+	errs      []error
+}
+
+func (r *templateResolver) errorAt(node *yamlv3.Node, format string, a ...any) error {
+	return LocatedError{
+		Position: Position{File: r.file, Line: node.Line, Column: node.Column},
+		Err:      fmt.Errorf(format, a...),
+	}
+}
+
+func (r *templateResolver) resolveTemplate(name string) *yamlv3.Node {
+	if resolved, found := r.resolved[name]; found {
+		return resolved
+	}
+
+	template := r.templates[name]
+	if template.Kind != yamlv3.MappingNode {
+		r.errs = append(r.errs, r.errorAt(template, "templates.%s : must be a task", name))
+		r.resolved[name] = nil
+		return nil
+	}
+
+	r.resolving[name] = true
+	resolved := r.extend(fmt.Sprintf("templates.%s", name), template)
+	r.resolving[name] = false
+
+	r.resolved[name] = resolved
+	return resolved
+}
+
+// extend merges the node with the template it extends, if any
+func (r *templateResolver) extend(path string, node *yamlv3.Node) *yamlv3.Node {
+	_, extends := mappingValue(node, extendsKey)
+	if extends == nil {
+		return node
+	}
+
+	if extends.Kind != yamlv3.ScalarNode {
+		r.errs = append(r.errs, r.errorAt(extends, "%s.extends : must be the name of a template", path))
+		return node
+	}
+
+	name := extends.Value
+	if _, found := r.templates[name]; !found {
+		r.errs = append(r.errs, r.errorAt(extends, "%s.extends : template '%s' is not defined. Must be one of %s", path, name, r.templateNames()))
+		return node
+	}
+
+	if r.resolving[name] {
+		r.errs = append(r.errs, r.errorAt(extends, "%s.extends : template '%s' extends itself", path, name))
+		return node
+	}
+
+	template := r.resolveTemplate(name)
+	if template == nil {
+		return node
+	}
+
+	return mergeNodes(template, withoutKey(node, extendsKey))
+}
+
+func (r *templateResolver) templateNames() string {
+	var names []string
+	for name := range r.templates {
+		names = append(names, fmt.Sprintf("'%s'", name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (r *templateResolver) resolveTaskList(path string, tasks *yamlv3.Node) *yamlv3.Node {
+	if tasks.Kind != yamlv3.SequenceNode {
+		return tasks
+	}
+
+	updated := *tasks
+	updated.Content = nil
+	for i, task := range tasks.Content {
+		updated.Content = append(updated.Content, r.resolveTask(fmt.Sprintf("%s[%d]", path, i), task))
+	}
+	return &updated
+}
+
+func (r *templateResolver) resolveTask(path string, task *yamlv3.Node) *yamlv3.Node {
+	if task.Kind != yamlv3.MappingNode {
+		return task
+	}
+
+	resolved := r.extend(path, task)
+	if resolved == task {
+		copied := *task
+		copied.Content = append([]*yamlv3.Node{}, task.Content...)
+		resolved = &copied
+	}
+
+	for _, key := range []string{"tasks", "pre_promote"} {
+		if i, nested := mappingValue(resolved, key); nested != nil {
+			resolved.Content[i] = r.resolveTaskList(path+"."+key, nested)
+		}
+	}
+	return resolved
+}
+
+func usesExtends(node *yamlv3.Node) bool {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == extendsKey || usesExtends(node.Content[i+1]) {
+				return true
+			}
+		}
+	case yamlv3.SequenceNode:
+		for _, item := range node.Content {
+			if usesExtends(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value node for the key and its index in the content of the mapping node
+func mappingValue(node *yamlv3.Node, key string) (int, *yamlv3.Node) {
+	if node.Kind != yamlv3.MappingNode {
+		return -1, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i + 1, node.Content[i+1]
+		}
+	}
+	return -1, nil
+}
+
+func withoutKey(node *yamlv3.Node, key string) *yamlv3.Node {
+	updated := *node
+	updated.Content = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			updated.Content = append(updated.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	return &updated
+}
+
+// mergeNodes returns a new mapping with the keys of base overridden by the keys of override.
+// Nested maps are merged, anything else in override replaces the value in base.
+// The new mapping has the position of override.
+func mergeNodes(base *yamlv3.Node, override *yamlv3.Node) *yamlv3.Node {
+	merged := *override
+	merged.Content = nil
+
+	for i := 0; i+1 < len(base.Content); i += 2 {
+		key, value := base.Content[i], base.Content[i+1]
+		if j, overrideValue := mappingValue(override, key.Value); overrideValue != nil {
+			key = override.Content[j-1]
+			if value.Kind == yamlv3.MappingNode && overrideValue.Kind == yamlv3.MappingNode {
+				value = mergeNodes(value, overrideValue)
+			} else {
+				value = overrideValue
+			}
+		}
+		merged.Content = append(merged.Content, key, value)
+	}
+
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		if _, found := mappingValue(base, override.Content[i].Value); found == nil {
+			merged.Content = append(merged.Content, override.Content[i], override.Content[i+1])
+		}
+	}
+
+	return &merged
+}
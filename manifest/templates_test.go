@@ -0,0 +1,164 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const templatesManifest = `team: my team
+pipeline: my pipeline
+
+templates:
+  deploy:
+    type: deploy-cf
+    api: api
+    space: qa
+    manifest: manifest.yml
+    vars:
+      A: a
+      B: b
+  deploy-live:
+    extends: deploy
+    space: live
+
+tasks:
+- name: qa
+  extends: deploy
+  vars:
+    B: overridden
+- type: parallel
+  tasks:
+  - name: live
+    extends: deploy-live
+    timeout: 2h
+- type: deploy-cf
+  name: with pre promote
+  pre_promote:
+  - type: run
+    name: smoke
+    script: ./smoke.sh
+  - name: nested
+    extends: deploy
+`
+
+func TestTemplatesAreExpanded(t *testing.T) {
+	man, errs := Parse(templatesManifest)
+	assert.Empty(t, errs)
+
+	expected := TaskList{
+		DeployCF{
+			Name:     "qa",
+			API:      "api",
+			Space:    "qa",
+			Manifest: "manifest.yml",
+			Vars:     Vars{"A": "a", "B": "overridden"},
+		},
+		Parallel{
+			Tasks: TaskList{
+				DeployCF{
+					Name:     "live",
+					API:      "api",
+					Space:    "live",
+					Manifest: "manifest.yml",
+					Vars:     Vars{"A": "a", "B": "b"},
+					Timeout:  "2h",
+				},
+			},
+		},
+		DeployCF{
+			Name: "with pre promote",
+			PrePromote: TaskList{
+				Run{Name: "smoke", Script: "./smoke.sh"},
+				DeployCF{
+					Name:     "nested",
+					API:      "api",
+					Space:    "qa",
+					Manifest: "manifest.yml",
+					Vars:     Vars{"A": "a", "B": "b"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, man.Tasks)
+}
+
+func TestTemplatesPositionsPointToTheTemplate(t *testing.T) {
+	_, sourceMap, errs := ParseWithSourceMap(".halfpipe.io", templatesManifest)
+	assert.Empty(t, errs)
+
+	pos, _ := sourceMap.Lookup("tasks[0]")
+	assert.Equal(t, ".halfpipe.io:18:3", pos.String())
+
+	pos, _ = sourceMap.Lookup("tasks[0].manifest")
+	assert.Equal(t, ".halfpipe.io:9:5", pos.String())
+
+	pos, _ = sourceMap.Lookup("tasks[0].vars.B")
+	assert.Equal(t, ".halfpipe.io:21:5", pos.String())
+
+	pos, _ = sourceMap.Lookup("tasks[1][0].space")
+	assert.Equal(t, ".halfpipe.io:15:5", pos.String())
+
+	pos, _ = sourceMap.Lookup("tasks[2].pre_promote[1].api")
+	assert.Equal(t, ".halfpipe.io:7:5", pos.String())
+
+	_, found := sourceMap["templates"]
+	assert.False(t, found)
+}
+
+func TestTemplatesErrors(t *testing.T) {
+	t.Run("unknown template", func(t *testing.T) {
+		_, _, errs := ParseWithSourceMap(".halfpipe.io", `
+templates:
+  a:
+    type: run
+tasks:
+- extends: b
+`)
+		if assert.Len(t, errs, 1) {
+			assert.EqualError(t, errs[0], ".halfpipe.io:6:12: tasks[0].extends : template 'b' is not defined. Must be one of 'a'")
+		}
+	})
+
+	t.Run("template extending itself", func(t *testing.T) {
+		_, _, errs := ParseWithSourceMap(".halfpipe.io", `
+templates:
+  a:
+    extends: b
+  b:
+    extends: a
+tasks:
+- extends: a
+`)
+		if assert.Len(t, errs, 1) {
+			assert.EqualError(t, errs[0], ".halfpipe.io:6:14: templates.b.extends : template 'a' extends itself")
+		}
+	})
+
+	t.Run("template is not a task", func(t *testing.T) {
+		_, _, errs := ParseWithSourceMap(".halfpipe.io", `
+templates:
+  a: blah
+tasks:
+- extends: a
+`)
+		if assert.Len(t, errs, 1) {
+			assert.EqualError(t, errs[0], ".halfpipe.io:3:6: templates.a : must be a task")
+		}
+	})
+
+	t.Run("unknown fields in template are reported against the template", func(t *testing.T) {
+		_, _, errs := ParseWithSourceMap(".halfpipe.io", `
+templates:
+  a:
+    type: run
+    blah: blah
+tasks:
+- extends: a
+`)
+		if assert.Len(t, errs, 1) {
+			assert.EqualError(t, errs[0], `.halfpipe.io:5:5: tasks[0] : unknown field "blah"`)
+		}
+	})
+}
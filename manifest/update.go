@@ -64,6 +64,18 @@ func (Update) IsManualTrigger() bool {
 	return false
 }
 
+func (Update) SkipsOnPullRequest() bool {
+	return false
+}
+
+func (Update) GetRunsOn() []string {
+	return nil
+}
+
+func (Update) GetWatchedPaths() []string {
+	return nil
+}
+
 func (Update) NotifiesOnSuccess() bool {
 	return false
 }
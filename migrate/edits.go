@@ -0,0 +1,159 @@
+package migrate
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// edit replaces the lines [start, end) of the document
+type edit struct {
+	start int
+	end   int
+	lines []string
+}
+
+// replaceKey replaces the entry of the key in the mapping with newKey and its value.
+// The line comment of the old entry is kept on the new key, the comments above it are left where they are.
+func (m *migrator) replaceKey(mapping *yaml.Node, key string, newKey string, value *yaml.Node) {
+	i, old := mappingValue(mapping, key)
+	if old == nil {
+		return
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: newKey, LineComment: mapping.Content[i-1].LineComment}
+	if keyNode.LineComment == "" && (old.Kind == yaml.ScalarNode || old.Style&yaml.FlowStyle != 0) {
+		keyNode.LineComment = old.LineComment
+	}
+
+	if start, end, ok := m.entryLines(mapping, i); ok {
+		// the comments above the key are not part of the lines of the entry
+		withoutHeadComment := *keyNode
+		encoded, err := encode(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{&withoutHeadComment, value}})
+		if err != nil {
+			m.reencode = true
+		} else {
+			indent := mapping.Content[i-1].Column - 1
+			lines := strings.Split(strings.TrimSuffix(encoded, "\n"), "\n")
+			for j := range lines {
+				if j == 0 {
+					lines[j] = m.lines[start][:indent] + lines[j]
+				} else {
+					lines[j] = strings.Repeat(" ", indent) + lines[j]
+				}
+				if j < len(lines)-1 {
+					lines[j] += "\n"
+				} else {
+					lines[j] += lineEnding(m.lines[end-1])
+				}
+			}
+			m.addEdit(edit{start, end, lines})
+		}
+	}
+
+	keyNode.HeadComment = mapping.Content[i-1].HeadComment
+	mapping.Content[i-1] = keyNode
+	mapping.Content[i] = value
+}
+
+// removeKey removes the entry of the key from the mapping, the comments above it are left where they are
+func (m *migrator) removeKey(mapping *yaml.Node, key string) bool {
+	i, value := mappingValue(mapping, key)
+	if value == nil {
+		return false
+	}
+
+	if start, end, ok := m.entryLines(mapping, i); ok {
+		indent := mapping.Content[i-1].Column - 1
+		prefix := m.lines[start][:indent]
+		switch {
+		case strings.TrimSpace(prefix) == "":
+			m.addEdit(edit{start, end, nil})
+		case i+1 < len(mapping.Content):
+			// the key follows the '-' of a sequence item, the next key of the item takes its place
+			next := mapping.Content[i+1].Line - 1
+			m.addEdit(edit{start, next + 1, []string{prefix + m.lines[next][indent:]}})
+		default:
+			m.addEdit(edit{start, end, []string{prefix + "{}" + lineEnding(m.lines[end-1])}})
+		}
+	}
+
+	mapping.Content = append(mapping.Content[:i-1], mapping.Content[i+1:]...)
+	return true
+}
+
+// entryLines returns the lines of the entry with the value at index i of the block mapping,
+// from the line of the key to the last line of the value
+func (m *migrator) entryLines(mapping *yaml.Node, i int) (start int, end int, ok bool) {
+	key := mapping.Content[i-1]
+	if mapping.Style&yaml.FlowStyle != 0 || key.Line == 0 || key.Line > len(m.lines) {
+		m.reencode = true
+		return 0, 0, false
+	}
+
+	indent := key.Column - 1
+	start = key.Line - 1
+	end = start + 1
+	for j := start + 1; j < len(m.lines); j++ {
+		line := strings.TrimRight(m.lines[j], "\r\n")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		// sequences in a mapping may start at the same indentation as their key
+		if lineIndent < indent || lineIndent == indent && trimmed != "-" && !strings.HasPrefix(trimmed, "- ") {
+			break
+		}
+		end = j + 1
+	}
+	return start, end, true
+}
+
+// addEdit adds the edit, replacing the edits within its lines. The document is encoded
+// from its nodes instead when edits overlap
+func (m *migrator) addEdit(e edit) {
+	var edits []edit
+	for _, existing := range m.edits {
+		switch {
+		case existing.start <= e.start && existing.end >= e.end:
+			return
+		case existing.start >= e.start && existing.end <= e.end:
+		case existing.end <= e.start || existing.start >= e.end:
+			edits = append(edits, existing)
+		default:
+			m.reencode = true
+			edits = append(edits, existing)
+		}
+	}
+	m.edits = append(edits, e)
+}
+
+// render returns the document with the edits applied to its lines
+func (m *migrator) render(document string, doc *yaml.Node) (string, error) {
+	if m.reencode {
+		migrated, err := encode(doc)
+		if err != nil {
+			return "", err
+		}
+		// the encoder drops the empty lines at the start of the document
+		return strings.Repeat("\n", leadingNewlines(document)) + migrated, nil
+	}
+
+	sort.Slice(m.edits, func(i, j int) bool { return m.edits[i].start > m.edits[j].start })
+	lines := append([]string{}, m.lines...)
+	for _, e := range m.edits {
+		lines = append(lines[:e.start], append(e.lines, lines[e.end:]...)...)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+func lineEnding(line string) string {
+	if strings.HasSuffix(line, "\r\n") {
+		return "\r\n"
+	}
+	if strings.HasSuffix(line, "\n") {
+		return "\n"
+	}
+	return ""
+}
@@ -0,0 +1,361 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/springernature/halfpipe/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest rewrites the deprecated fields in the halfpipe manifest to the current structure,
+// in the same way the mapper interprets them when the pipeline is rendered.
+//
+// Only the lines of the changed fields are rewritten, so the rest of the document keeps its formatting and comments.
+// Documents without deprecated fields are returned as they are.
+func Manifest(manifestYaml string) (migrated string, changes []string, err error) {
+	documents, separators := splitDocuments(manifestYaml)
+
+	var out strings.Builder
+	for i, document := range documents {
+		if i > 0 {
+			out.WriteString(separators[i-1])
+		}
+
+		m := migrator{}
+		migratedDocument, err := m.migrateDocument(document)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(documents) > 1 {
+			for j, change := range m.changes {
+				m.changes[j] = fmt.Sprintf("document %d: %s", i+1, change)
+			}
+		}
+		changes = append(changes, m.changes...)
+		out.WriteString(migratedDocument)
+	}
+
+	return out.String(), changes, nil
+}
+
+// CFManifest rewrites the deprecated 'buildpack' of the apps in a Cloud Foundry manifest to 'buildpacks'
+func CFManifest(cfManifestYaml string) (migrated string, changes []string, err error) {
+	m := migrator{}
+	doc, err := m.parse(cfManifestYaml)
+	if err != nil || doc == nil {
+		return cfManifestYaml, nil, err
+	}
+
+	_, apps := mappingValue(doc.Content[0], "applications")
+	if apps != nil && apps.Kind == yaml.SequenceNode {
+		for i, app := range apps.Content {
+			_, buildpack := mappingValue(app, "buildpack")
+			if buildpack == nil || buildpack.Kind != yaml.ScalarNode {
+				continue
+			}
+			if _, buildpacks := mappingValue(app, "buildpacks"); buildpacks != nil {
+				continue
+			}
+			m.replaceKey(app, "buildpack", "buildpacks", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{buildpack}})
+			m.change("applications[%d] : 'buildpack' is now 'buildpacks'", i)
+		}
+	}
+
+	if len(m.changes) == 0 {
+		return cfManifestYaml, nil, nil
+	}
+	migrated, err = m.render(cfManifestYaml, doc)
+	return migrated, m.changes, err
+}
+
+type migrator struct {
+	changes []string
+
+	// lines of the document, the changed entries are replaced in them so the rest of the document keeps its formatting
+	lines    []string
+	edits    []edit
+	reencode bool
+
+	topLevel       manifest.Notifications
+	successMessage string
+	templates      map[string]*yaml.Node
+}
+
+func (m *migrator) change(format string, a ...any) {
+	m.changes = append(m.changes, fmt.Sprintf(format, a...))
+}
+
+// parse returns the yaml document, or nil if it is empty or not a map
+func (m *migrator) parse(document string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(document), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	m.lines = strings.SplitAfter(document, "\n")
+	return &doc, nil
+}
+
+func (m *migrator) migrateDocument(document string) (string, error) {
+	doc, err := m.parse(document)
+	if err != nil || doc == nil {
+		return document, err
+	}
+	root := doc.Content[0]
+
+	m.topLevelNotifications(root)
+
+	if _, templates := mappingValue(root, "templates"); templates != nil && templates.Kind == yaml.MappingNode {
+		m.templates = map[string]*yaml.Node{}
+		for i := 0; i+1 < len(templates.Content); i += 2 {
+			m.templates[templates.Content[i].Value] = templates.Content[i+1]
+		}
+		for i := 0; i+1 < len(templates.Content); i += 2 {
+			m.task("templates."+templates.Content[i].Value, templates.Content[i+1], false)
+		}
+	}
+
+	if _, tasks := mappingValue(root, "tasks"); tasks != nil {
+		m.tasks("tasks", tasks)
+	}
+
+	if m.removeKey(root, "slack_success_message") {
+		m.change("'slack_success_message' is removed, it is now the message of the success notifications of the tasks")
+	}
+
+	if len(m.changes) == 0 {
+		return document, nil
+	}
+
+	return m.render(document, doc)
+}
+
+// topLevelNotifications moves 'slack_channel', 'slack_failure_message' and 'teams_webhook' to 'notifications',
+// they are only used when 'notifications' is not set
+func (m *migrator) topLevelNotifications(root *yaml.Node) {
+	_, notificationsNode := mappingValue(root, "notifications")
+	if notificationsNode != nil {
+		_ = notificationsNode.Decode(&m.topLevel)
+	}
+	m.successMessage = scalar(root, "slack_success_message")
+
+	used := m.topLevel.Equal(manifest.Notifications{})
+	if used {
+		var failure manifest.NotificationChannels
+		if slackChannel := scalar(root, "slack_channel"); slackChannel != "" {
+			failure = append(failure, manifest.NotificationChannel{Slack: slackChannel, Message: scalar(root, "slack_failure_message")})
+		}
+		if teamsWebhook := scalar(root, "teams_webhook"); teamsWebhook != "" {
+			failure = append(failure, manifest.NotificationChannel{Teams: teamsWebhook})
+		}
+
+		if len(failure) > 0 {
+			m.topLevel = manifest.Notifications{Failure: failure}
+			value := encodeNode(m.topLevel)
+			if notificationsNode != nil {
+				m.replaceKey(root, "notifications", "notifications", value)
+			} else {
+				// notifications takes the place of the first deprecated field
+				for j := 0; j+1 < len(root.Content); j += 2 {
+					if key := root.Content[j].Value; key == "slack_channel" || key == "teams_webhook" {
+						m.replaceKey(root, key, "notifications", value)
+						m.change("'%s' is now 'notifications.failure'", key)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for _, key := range []string{"slack_channel", "slack_failure_message", "teams_webhook"} {
+		switch {
+		case !m.removeKey(root, key):
+		case used:
+			m.change("'%s' is now 'notifications.failure'", key)
+		default:
+			m.change("'%s' is removed, it has no effect as 'notifications' is set", key)
+		}
+	}
+}
+
+func (m *migrator) tasks(path string, tasks *yaml.Node) {
+	if tasks.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, task := range tasks.Content {
+		m.task(fmt.Sprintf("%s[%d]", path, i), task, true)
+	}
+}
+
+// task migrates the deprecated fields of the task, notify_on_success is only migrated in tasks of the pipeline
+// as its meaning depends on the notifications of the task after templates are applied
+func (m *migrator) task(path string, task *yaml.Node, inPipeline bool) {
+	if task.Kind != yaml.MappingNode {
+		return
+	}
+
+	switch m.taskType(task) {
+	case "parallel", "sequence":
+		if _, tasks := mappingValue(task, "tasks"); tasks != nil {
+			m.tasks(path+".tasks", tasks)
+		}
+		return
+	case "docker-push":
+		if m.removeKey(task, "tag") {
+			m.change("%s : 'tag' is removed, it is no longer used", path)
+		}
+	}
+
+	m.taskNotifications(path, task)
+
+	_, matrix := mappingValue(task, "matrix")
+	if inPipeline && scalar(task, "extends") == "" && matrix == nil {
+		m.notifyOnSuccess(path, task)
+	}
+}
+
+// taskType returns the type of the task, or of the template it extends
+func (m *migrator) taskType(task *yaml.Node) string {
+	for extended := 0; task != nil && extended <= len(m.templates); extended++ {
+		if taskType := scalar(task, "type"); taskType != "" {
+			return taskType
+		}
+		task = m.templates[scalar(task, "extends")]
+	}
+	return ""
+}
+
+// taskNotifications moves 'on_failure' and 'on_success' with their messages to 'failure' and 'success',
+// they are only used when neither 'failure' nor 'success' are set
+func (m *migrator) taskNotifications(path string, task *yaml.Node) {
+	_, notificationsNode := mappingValue(task, "notifications")
+	if notificationsNode == nil || notificationsNode.Kind != yaml.MappingNode {
+		return
+	}
+
+	var notifications manifest.Notifications
+	if err := notificationsNode.Decode(&notifications); err != nil {
+		return
+	}
+
+	if len(notifications.Failure) == 0 && len(notifications.Success) == 0 {
+		replace := func(old string, new string, channels []string, message string) {
+			if len(channels) == 0 {
+				return
+			}
+			var value manifest.NotificationChannels
+			for _, channel := range channels {
+				value = append(value, manifest.NotificationChannel{Slack: channel, Message: message})
+			}
+			m.replaceKey(notificationsNode, old, new, encodeNode(value))
+			m.change("%s.notifications : '%s' is now '%s'", path, old, new)
+		}
+		replace("on_failure", "failure", notifications.OnFailure, notifications.OnFailureMessage)
+		replace("on_success", "success", notifications.OnSuccess, notifications.OnSuccessMessage)
+	}
+
+	for _, key := range []string{"on_failure", "on_failure_message", "on_success", "on_success_message"} {
+		if m.removeKey(notificationsNode, key) {
+			m.change("%s.notifications : '%s' is removed", path, key)
+		}
+	}
+
+	if len(notificationsNode.Content) == 0 {
+		m.removeKey(task, "notifications")
+	}
+}
+
+// notifyOnSuccess replaces 'notify_on_success' with the notifications it stands for,
+// the top level notifications and a success notification to the first slack channel and teams webhook
+func (m *migrator) notifyOnSuccess(path string, task *yaml.Node) {
+	_, notifyOnSuccess := mappingValue(task, "notify_on_success")
+	if notifyOnSuccess == nil {
+		return
+	}
+
+	_, notificationsNode := mappingValue(task, "notifications")
+	if notifyOnSuccess.Value != "true" || notificationsNode != nil || !m.topLevel.NotificationsDefined() {
+		m.removeKey(task, "notify_on_success")
+		m.change("%s : 'notify_on_success' is removed, it has no effect", path)
+		return
+	}
+
+	notifications := manifest.Notifications{
+		Failure: m.topLevel.Failure,
+		Success: append(manifest.NotificationChannels{}, m.topLevel.Success...),
+	}
+	if slack := m.topLevel.Failure.Slack(); len(slack) > 0 {
+		notifications.Success = append(notifications.Success, manifest.NotificationChannel{Slack: slack[0].Slack, Message: m.successMessage})
+	}
+	if teams := m.topLevel.Failure.Teams(); len(teams) > 0 {
+		notifications.Success = append(notifications.Success, teams[0])
+	}
+
+	m.replaceKey(task, "notify_on_success", "notifications", encodeNode(notifications))
+	m.change("%s : 'notify_on_success' is now 'notifications.success'", path)
+}
+
+func encodeNode(value any) *yaml.Node {
+	var node yaml.Node
+	_ = node.Encode(value)
+	return &node
+}
+
+func encode(doc *yaml.Node) (string, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// mappingValue returns the value node for the key and its index in the content of the mapping node
+func mappingValue(node *yaml.Node, key string) (int, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return -1, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i + 1, node.Content[i+1]
+		}
+	}
+	return -1, nil
+}
+
+func scalar(node *yaml.Node, key string) string {
+	if _, value := mappingValue(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+var documentSeparator = regexp.MustCompile(`^---(\s.*)?$`)
+
+// splitDocuments splits the yaml into its documents and the separator lines between them
+func splitDocuments(yamlString string) (documents []string, separators []string) {
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(yamlString, "\n") {
+		if documentSeparator.MatchString(strings.TrimRight(line, "\r\n")) {
+			documents = append(documents, current.String())
+			separators = append(separators, line)
+			current.Reset()
+			continue
+		}
+		current.WriteString(line)
+	}
+	return append(documents, current.String()), separators
+}
+
+func leadingNewlines(document string) int {
+	return len(document) - len(strings.TrimLeft(document, "\n"))
+}
@@ -0,0 +1,338 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/mapper"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertSameNotifications(t *testing.T, original string, migrated string) {
+	t.Helper()
+	originalManifest, errs := manifest.Parse(original)
+	assert.Empty(t, errs)
+	migratedManifest, errs := manifest.Parse(migrated)
+	assert.Empty(t, errs)
+
+	expected, _ := mapper.NewNotificationsMapper().Apply(originalManifest)
+	actual, _ := mapper.NewNotificationsMapper().Apply(migratedManifest)
+	assert.Equal(t, expected.Notifications, actual.Notifications)
+	assert.Len(t, actual.Tasks.Flatten(), len(expected.Tasks.Flatten()))
+	for i, task := range expected.Tasks.Flatten() {
+		assert.Equal(t, task.GetNotifications(), actual.Tasks.Flatten()[i].GetNotifications(), task.GetName())
+	}
+}
+
+func TestNothingToMigrate(t *testing.T) {
+	manifestYaml := `team: my-team
+pipeline: my-pipeline
+
+# keeps the formatting as it is
+notifications:
+  failure:
+  - slack: "#ee-re"
+tasks:
+- type: run
+  name: test
+  script: ./test.sh
+  docker: {image: alpine}
+`
+
+	migrated, changes, err := Manifest(manifestYaml)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, manifestYaml, migrated)
+}
+
+func TestMigratesNotifications(t *testing.T) {
+	manifestYaml := `# my pipeline
+team: my-team
+pipeline: my-pipeline
+slack_channel: "#ee-re" # the team channel
+slack_failure_message: failed
+slack_success_message: succeeded
+tasks:
+- type: run
+  name: test
+  script: ./test.sh
+  docker:
+    image: alpine
+  notifications:
+    # the release channel
+    on_failure:
+    - "#ee-release"
+    on_failure_message: test failed
+- type: parallel
+  tasks:
+  - type: deploy-cf
+    name: deploy
+    api: api
+    space: live
+    notify_on_success: true
+  - type: docker-push
+    name: push
+    image: eu.gcr.io/halfpipe-io/my-image
+    tag: version
+`
+
+	migrated, changes, err := Manifest(manifestYaml)
+	assert.NoError(t, err)
+	assert.Equal(t, `# my pipeline
+team: my-team
+pipeline: my-pipeline
+notifications: # the team channel
+  failure:
+    - slack: '#ee-re'
+      message: failed
+tasks:
+- type: run
+  name: test
+  script: ./test.sh
+  docker:
+    image: alpine
+  notifications:
+    # the release channel
+    failure:
+      - slack: '#ee-release'
+        message: test failed
+- type: parallel
+  tasks:
+  - type: deploy-cf
+    name: deploy
+    api: api
+    space: live
+    notifications:
+      success:
+        - slack: '#ee-re'
+          message: succeeded
+      failure:
+        - slack: '#ee-re'
+          message: failed
+  - type: docker-push
+    name: push
+    image: eu.gcr.io/halfpipe-io/my-image
+`, migrated)
+
+	assert.Equal(t, []string{
+		"'slack_channel' is now 'notifications.failure'",
+		"'slack_failure_message' is now 'notifications.failure'",
+		"tasks[0].notifications : 'on_failure' is now 'failure'",
+		"tasks[0].notifications : 'on_failure_message' is removed",
+		"tasks[1].tasks[0] : 'notify_on_success' is now 'notifications.success'",
+		"tasks[1].tasks[1] : 'tag' is removed, it is no longer used",
+		"'slack_success_message' is removed, it is now the message of the success notifications of the tasks",
+	}, changes)
+
+	assertSameNotifications(t, manifestYaml, migrated)
+}
+
+func TestKeepsFormattingAndComments(t *testing.T) {
+	manifestYaml := `# my pipeline
+team: my-team
+pipeline: my-pipeline
+
+# the team channel
+slack_channel: "#ee-re" # ask here
+slack_failure_message: failed
+
+tasks:
+# tests
+- type: run
+  name: test
+  script: ./test.sh
+  docker: {image: alpine}
+  # tell everyone
+  notify_on_success: true # when it works
+
+# deploys
+- notify_on_success: false
+  type: deploy-cf
+  name: deploy
+  notifications:
+    # the release channel
+    on_failure: ["#ee-release"] # only failures
+    on_failure_message: "deploy failed"
+    on_success:
+        - "#ee-deploys"
+
+- type: docker-push
+  name: push
+  image: eu.gcr.io/halfpipe-io/my-image
+  tag: gitref
+  # the last field
+`
+
+	migrated, _, err := Manifest(manifestYaml)
+	assert.NoError(t, err)
+	assert.Equal(t, `# my pipeline
+team: my-team
+pipeline: my-pipeline
+
+# the team channel
+notifications: # ask here
+  failure:
+    - slack: '#ee-re'
+      message: failed
+
+tasks:
+# tests
+- type: run
+  name: test
+  script: ./test.sh
+  docker: {image: alpine}
+  # tell everyone
+  notifications: # when it works
+    success:
+      - slack: '#ee-re'
+    failure:
+      - slack: '#ee-re'
+        message: failed
+
+# deploys
+- type: deploy-cf
+  name: deploy
+  notifications:
+    # the release channel
+    failure: # only failures
+      - slack: '#ee-release'
+        message: deploy failed
+    success:
+      - slack: '#ee-deploys'
+
+- type: docker-push
+  name: push
+  image: eu.gcr.io/halfpipe-io/my-image
+  # the last field
+`, migrated)
+
+	assertSameNotifications(t, manifestYaml, migrated)
+}
+
+func TestOldNotificationsAreRemovedWhenNewOnesAreSet(t *testing.T) {
+	manifestYaml := `team: my-team
+pipeline: my-pipeline
+slack_channel: "#ignored"
+notifications:
+  failure:
+  - teams: https://teams
+tasks:
+- type: run
+  name: test
+  notify_on_success: true
+  notifications:
+    on_success:
+    - "#ignored"
+    success:
+    - slack: "#ee-re"
+- type: run
+  name: only a message
+  notifications:
+    on_failure_message: ignored
+- type: run
+  name: notify
+  notify_on_success: true
+`
+
+	migrated, _, err := Manifest(manifestYaml)
+	assert.NoError(t, err)
+	assert.Equal(t, `team: my-team
+pipeline: my-pipeline
+notifications:
+  failure:
+  - teams: https://teams
+tasks:
+- type: run
+  name: test
+  notifications:
+    success:
+    - slack: "#ee-re"
+- type: run
+  name: only a message
+- type: run
+  name: notify
+  notifications:
+    success:
+      - teams: https://teams
+    failure:
+      - teams: https://teams
+`, migrated)
+
+	assertSameNotifications(t, manifestYaml, migrated)
+}
+
+func TestMigratesTemplates(t *testing.T) {
+	manifestYaml := `team: my-team
+pipeline: my-pipeline
+templates:
+  push:
+    type: docker-push
+    tag: gitref
+    notifications:
+      on_failure:
+      - "#ee-re"
+tasks:
+- name: push
+  extends: push
+  image: eu.gcr.io/halfpipe-io/my-image
+- name: push again
+  extends: push
+  image: eu.gcr.io/halfpipe-io/my-other-image
+  tag: version
+`
+
+	migrated, changes, err := Manifest(manifestYaml)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"templates.push : 'tag' is removed, it is no longer used",
+		"templates.push.notifications : 'on_failure' is now 'failure'",
+		"tasks[1] : 'tag' is removed, it is no longer used",
+	}, changes)
+	assertSameNotifications(t, manifestYaml, migrated)
+}
+
+func TestOnlyChangedDocumentsAreRewritten(t *testing.T) {
+	unchanged := `team: my-team
+pipeline: first
+tasks:
+- {type: run, name: test, script: ./test.sh, docker: {image: alpine}}
+`
+	manifestYaml := unchanged + `---
+team: my-team
+pipeline: second
+slack_channel: "#ee-re"
+`
+
+	migrated, changes, err := Manifest(manifestYaml)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"document 2: 'slack_channel' is now 'notifications.failure'"}, changes)
+	assert.Equal(t, unchanged+`---
+team: my-team
+pipeline: second
+notifications:
+  failure:
+    - slack: '#ee-re'
+`, migrated)
+}
+
+func TestCFManifest(t *testing.T) {
+	migrated, changes, err := CFManifest(`applications:
+- name: my-app
+  # the java buildpack
+  buildpack: java_buildpack
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"applications[0] : 'buildpack' is now 'buildpacks'"}, changes)
+	assert.Equal(t, `applications:
+- name: my-app
+  # the java buildpack
+  buildpacks:
+    - java_buildpack
+`, migrated)
+
+	unchanged := "applications:\n- name: my-app\n  buildpacks: [java_buildpack]\n"
+	migrated, changes, err = CFManifest(unchanged)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, unchanged, migrated)
+}
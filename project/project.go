@@ -5,6 +5,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/tcnksm/go-gitconfig"
+	"golang.org/x/exp/slices"
+	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
@@ -129,3 +131,33 @@ func (c projectResolver) GetHalfpipeFileName(workingDir string, halfpipeFilename
 
 	return foundPaths[0], nil
 }
+
+// ignoredDirs are not searched for halfpipe manifests
+var ignoredDirs = []string{".git", "node_modules", "vendor"}
+
+// FindHalfpipeFiles returns the directories under the git root that contain a halfpipe manifest, for repos with several pipelines
+func (c projectResolver) FindHalfpipeFiles(gitRootPath string, halfpipeFilenameOptions []string) (dirs []string, err error) {
+	err = c.Fs.Walk(gitRootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != gitRootPath && slices.Contains(ignoredDirs, info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		if slices.Contains(halfpipeFilenameOptions, info.Name()) && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+
+	if err == nil && len(dirs) == 0 {
+		err = fmt.Errorf("%w : %s in %s", ErrHalfpipeFileNotFound, halfpipeFilenameOptions, gitRootPath)
+	}
+	return dirs, err
+}
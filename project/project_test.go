@@ -181,3 +181,21 @@ func TestFindsHalfpipeFile(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestFindHalfpipeFiles(t *testing.T) {
+	pr := testProjectResolver()
+	pr.Fs.MkdirAll("/repo/.git", 0777)
+	pr.Fs.Create("/repo/.halfpipe.io")
+	pr.Fs.Create("/repo/services/api/.halfpipe.io")
+	pr.Fs.Create("/repo/services/api/.halfpipe.io.yml")
+	pr.Fs.Create("/repo/services/web/.halfpipe.io.yml")
+	pr.Fs.Create("/repo/services/web/node_modules/dep/.halfpipe.io")
+	pr.Fs.Create("/repo/services/worker/README.md")
+
+	dirs, err := pr.FindHalfpipeFiles("/repo", config.HalfpipeFilenameOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/repo", "/repo/services/api", "/repo/services/web"}, dirs)
+
+	_, err = pr.FindHalfpipeFiles("/repo/services/worker", config.HalfpipeFilenameOptions)
+	assert.ErrorIs(t, err, ErrHalfpipeFileNotFound)
+}
@@ -3,12 +3,10 @@ package actions
 import (
 	"fmt"
 	"golang.org/x/exp/slices"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/springernature/halfpipe/config"
-	"github.com/springernature/halfpipe/renderers/shared"
 
 	"github.com/springernature/halfpipe/manifest"
 )
@@ -28,16 +26,25 @@ type Actions struct {
 	gitURI           string
 	workingDir       string
 	halfpipeFilePath string
+	// skippableJobs are the jobs that can be skipped because of watched paths of tasks
+	skippableJobs []string
 }
 
 func NewActions(gitURI string, halfpipeFilePath string) Actions {
 	return Actions{gitURI: gitURI, halfpipeFilePath: halfpipeFilePath}
 }
 
-func (a Actions) PlatformURL(man manifest.Manifest) string {
+func (a Actions) repoURL() string {
 	url := strings.Replace(a.gitURI, "git@github.com:", "https://github.com/", 1)
-	url = strings.TrimSuffix(url, ".git")
-	return fmt.Sprintf("%s/actions?query=workflow:%s", url, man.PipelineName())
+	return strings.TrimSuffix(url, ".git")
+}
+
+func (a Actions) PlatformURL(man manifest.Manifest) string {
+	return fmt.Sprintf("%s/actions?query=workflow:%s", a.repoURL(), man.PipelineName())
+}
+
+func (a Actions) EnvironmentsURL() string {
+	return a.repoURL() + "/settings/environments"
 }
 
 func (a Actions) Render(man manifest.Manifest) (string, error) {
@@ -45,8 +52,20 @@ func (a Actions) Render(man manifest.Manifest) (string, error) {
 	w.Name = man.Pipeline
 	w.On = a.triggers(man)
 	w.Concurrency = "${{ github.workflow }}"
+	if man.Triggers.HasPullRequestTrigger() {
+		// runs of pull requests must not queue behind the runs of the branch or each other
+		w.Concurrency = "${{ github.workflow }}-${{ github.ref }}"
+	}
 	if len(man.Tasks) > 0 {
 		w.Env = globalEnv
+		if man.Triggers.HasTagTrigger() {
+			w.Env = Env{}
+			for k, v := range globalEnv {
+				w.Env[k] = v
+			}
+			// the workflow can also be dispatched manually, then there is no tag to take the version from
+			w.Env["BUILD_VERSION"] = "${{ github.ref_type == 'tag' && github.ref_name || format('2.{0}.0', github.run_number) }}"
+		}
 		gitTrigger := man.Triggers.GetGitTrigger()
 		if gitTrigger.BasePath != "" {
 			w.Defaults.Run.WorkingDirectory = gitTrigger.BasePath
@@ -57,6 +76,9 @@ func (a Actions) Render(man manifest.Manifest) (string, error) {
 		}
 
 		w.Jobs = a.jobs(man.Tasks, man, nil)
+		if changes, found := a.changesJob(man); found {
+			w.Jobs = append(Jobs{{Key: changesJobID, Value: changes}}, w.Jobs...)
+		}
 	}
 	return w.asYAML()
 }
@@ -68,6 +90,15 @@ type parentTask struct {
 
 func (a *Actions) jobs(tasks manifest.TaskList, man manifest.Manifest, parent *parentTask) (jobs Jobs) {
 	appendJob := func(taskSteps Steps, task manifest.Task, needs []string) {
+		// the outputs of a job can only be used by the jobs that need it
+		for _, ref := range manifest.TaskOutputReferences(task) {
+			if !slices.Contains(needs, ref.Task) {
+				needs = append(slices.Clone(needs), ref.Task)
+			}
+		}
+
+		needs, conditions := a.watchedPathsConditions(task, needs, man)
+
 		steps := checkoutCode(man.Triggers.GetGitTrigger())
 		if task.ReadsFromArtifacts() {
 			steps = append(steps, a.restoreArtifacts()...)
@@ -79,20 +110,54 @@ func (a *Actions) jobs(tasks manifest.TaskList, man manifest.Manifest, parent *p
 			steps = append(steps, notify(notifications)...)
 		}
 
+		if _, isUpdate := task.(manifest.Update); !isUpdate {
+			for _, repository := range man.DispatchJobStatus {
+				steps = append(steps, pipelineDispatch(man, task.GetName(), repository))
+			}
+		}
+
 		job := Job{
 			Name:           task.GetName(),
-			RunsOn:         config.ActionsRunnerName,
+			RunsOn:         runsOn(task),
+			Permissions:    supplyChainPermissions(task),
 			Steps:          convertSecrets(steps, man.Team),
 			TimeoutMinutes: timeoutInMinutes(task.GetTimeout()),
 			Needs:          needs,
 		}
 
+		if task.IsManualTrigger() {
+			job.Environment = environment(task)
+		}
+
+		if run, ok := task.(manifest.Run); ok && run.Container.Image != "" {
+			job.Container = Container{
+				Image:       run.Container.Image,
+				Credentials: Credentials{Username: run.Container.Username, Password: run.Container.Password},
+			}
+		}
+
 		if job.Name == "update" {
 			job.Outputs = Outputs{"synced": "${{ steps.sync.outputs.synced }}"}
 		}
+		for key := range manifest.TaskOutputs(task) {
+			if job.Outputs == nil {
+				job.Outputs = Outputs{}
+			}
+			job.Outputs[key] = fmt.Sprintf("${{ steps.outputs.outputs.%s }}", key)
+		}
+
 		if slices.Contains(needs, "update") {
-			job.If = "needs.update.outputs.synced == 'true'"
+			conditions = append(conditions, "needs.update.outputs.synced == 'true'")
+		}
+		if man.Triggers.HasPullRequestTrigger() && task.SkipsOnPullRequest() {
+			conditions = append(conditions, "github.event_name != 'pull_request'")
 		}
+		if condition := workflowRunCondition(man); condition != "" && len(conditions) > 0 {
+			conditions = append(conditions, fmt.Sprintf("(%s)", condition))
+		} else if condition != "" {
+			conditions = append(conditions, condition)
+		}
+		job.If = strings.Join(conditions, " && ")
 
 		jobs = append(jobs, Jobs{{Key: idFromName(job.Name), Value: job}}[0])
 	}
@@ -107,34 +172,29 @@ func (a *Actions) jobs(tasks manifest.TaskList, man manifest.Manifest, parent *p
 		switch task := t.(type) {
 		case manifest.Update:
 			appendJob(a.updateSteps(task, man), task, needs)
-		case manifest.DockerPush:
-			appendJob(a.dockerPushSteps(task), task, needs)
-		case manifest.Run:
-			appendJob(a.runSteps(task), task, needs)
-		case manifest.DockerCompose:
-			appendJob(a.dockerComposeSteps(task, man.Team), task, needs)
-		case manifest.ConsumerIntegrationTest:
-			appendJob(a.consumerIntegrationTestSteps(task, man), task, needs)
-		case manifest.DeployMLModules:
-			runTask := shared.ConvertDeployMLModules(task, man)
-			appendJob(a.runSteps(runTask), task, needs)
-		case manifest.DeployMLZip:
-			runTask := shared.ConvertDeployMLZip(task, man)
-			appendJob(a.runSteps(runTask), task, needs)
-		case manifest.DeployCF:
-			appendJob(a.deployCFSteps(task, man), task, needs)
-		case manifest.DeployKatee:
-			appendJob(a.deployKateeSteps(task), task, needs)
 		case manifest.Parallel:
 			jobs = append(jobs, a.jobs(task.Tasks, man, &parentTask{isParallel: true, needs: needs})...)
 		case manifest.Sequence:
 			jobs = append(jobs, a.jobs(task.Tasks, man, &parentTask{isParallel: false, needs: needs})...)
+		default:
+			render, found := taskRenderers[manifest.TaskType(task)]
+			if !found {
+				panic(fmt.Sprintf("no GitHub Actions renderer is registered for the task '%s' of type %T", task.GetName(), task))
+			}
+			appendJob(render(a, task, man), task, needs)
 		}
 	}
 
 	return jobs
 }
 
+func runsOn(task manifest.Task) RunsOn {
+	if labels := task.GetRunsOn(); len(labels) > 0 {
+		return labels
+	}
+	return RunsOn{config.ActionsRunnerName}
+}
+
 func checkoutCode(gitTrigger manifest.GitTrigger) Steps {
 	checkout := Step{
 		Name: "Checkout code",
@@ -166,8 +226,7 @@ func timeoutInMinutes(timeout string) int {
 }
 
 func idFromName(name string) string {
-	re := regexp.MustCompile(`[^a-z_0-9\-]`)
-	return re.ReplaceAllString(strings.ToLower(name), "_")
+	return manifest.TaskID(name)
 }
 
 func idsFromNames(names []string) []string {
@@ -178,8 +237,8 @@ func idsFromNames(names []string) []string {
 }
 
 func dockerLogin(image, username, password string) Steps {
-	// check login step is needed
-	if username == "" || strings.HasPrefix(image, config.DockerRegistry) {
+	// check login step is needed, the runners are logged in to the registries of the organisation
+	if username == "" || config.IsAllowedDockerRegistry(image) {
 		return Steps{}
 	}
 
@@ -192,12 +251,24 @@ func dockerLogin(image, username, password string) Steps {
 		},
 	}
 
-	// set registry if not docker hub by counting slashes
-	// docker hub format: repository:tag or user/repository:tag
-	// other registries:  another.registry/user/repository:tag
-	if strings.Count(image, "/") > 1 {
-		registry := strings.Split(image, "/")[0]
+	// set registry if not docker hub
+	if registry := config.DockerRegistryHost(image); registry != "" {
 		step.With["registry"] = registry
 	}
 	return Steps{step}
 }
+
+// environment is the GitHub environment of a task with manual_trigger, the job waits for the required reviewers of it
+func environment(task manifest.Task) string {
+	return task.GetName()
+}
+
+// Environments returns the GitHub environments that must be created for the workflow of the manifest
+func Environments(man manifest.Manifest) (environments []string) {
+	for _, task := range man.Tasks.Flatten() {
+		if task.IsManualTrigger() && !slices.Contains(environments, environment(task)) {
+			environments = append(environments, environment(task))
+		}
+	}
+	return environments
+}
@@ -0,0 +1,45 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/springernature/halfpipe/config"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironments(t *testing.T) {
+	man := manifest.Manifest{
+		Tasks: manifest.TaskList{
+			manifest.Run{Name: "test"},
+			manifest.Parallel{Tasks: manifest.TaskList{
+				manifest.DeployCF{Name: "deploy to live", ManualTrigger: true},
+				manifest.Run{Name: "smoke test", ManualTrigger: true},
+			}},
+			manifest.DeployCF{Name: "deploy to live", ManualTrigger: true},
+		},
+	}
+
+	assert.Equal(t, []string{"deploy to live", "smoke test"}, Environments(man))
+	assert.Empty(t, Environments(manifest.Manifest{Tasks: manifest.TaskList{manifest.Run{Name: "test"}}}))
+}
+
+func TestPanicsForTaskWithoutRenderer(t *testing.T) {
+	defer func(renderer TaskRenderer) { RegisterTaskRenderer("run", renderer) }(taskRenderers["run"])
+	delete(taskRenderers, "run")
+
+	man := manifest.Manifest{Tasks: manifest.TaskList{manifest.Run{Name: "test"}}}
+	assert.PanicsWithValue(t, "no GitHub Actions renderer is registered for the task 'test' of type manifest.Run", func() {
+		_, _ = NewActions("", "").Render(man)
+	})
+}
+
+func TestCompositeActionVersion(t *testing.T) {
+	defer func(version string) { config.Version = version }(config.Version)
+
+	config.Version = "0.0.0-DEV"
+	assert.Equal(t, "springernature/halfpipe/actions/deploy-cf@main", compositeAction("deploy-cf"))
+
+	config.Version = "3.120.0"
+	assert.Equal(t, "springernature/halfpipe/actions/deploy-cf@3.120.0", compositeAction("deploy-cf"))
+}
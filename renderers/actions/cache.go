@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/springernature/halfpipe/manifest"
+)
+
+// cacheStep restores the cached paths of the task and saves them when the job succeeds.
+// The cache is keyed on the hash of the key files, without key files a new cache is saved on every run.
+func (a *Actions) cacheStep(taskName string, cache manifest.Cache) Step {
+	var paths []string
+	for _, p := range cache.Paths {
+		paths = append(paths, path.Join(a.workingDir, p))
+	}
+
+	var keyFiles []string
+	for _, f := range cache.KeyFiles {
+		keyFiles = append(keyFiles, fmt.Sprintf("'%s'", path.Join(a.workingDir, f)))
+	}
+
+	prefix := fmt.Sprintf("${{ github.workflow }}-%s-", idFromName(taskName))
+	key := prefix + "${{ github.sha }}"
+	if len(keyFiles) > 0 {
+		key = fmt.Sprintf("%s${{ hashFiles(%s) }}", prefix, strings.Join(keyFiles, ", "))
+	}
+
+	return Step{
+		Name: "Cache",
+		Uses: "actions/cache@v4",
+		With: With{
+			"path":         strings.Join(paths, "\n"),
+			"key":          key,
+			"restore-keys": prefix,
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package actions
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/springernature/halfpipe/config"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/renderers/shared"
+)
+
+// compositeAction is the reference to one of the composite actions in /actions of the halfpipe repo.
+// The version of the action is the version of halfpipe that rendered the workflow, so updating the workflow updates the action.
+func compositeAction(name string) string {
+	ref := config.Version
+	if ref == "" || ref == config.DevVersion.String() {
+		ref = "main"
+	}
+	return fmt.Sprintf("%s/actions/%s@%s", config.ActionsRepository, name, ref)
+}
+
+// deployCFActionSteps calls the deploy-cf composite action with the parameters of the push step.
+// Pre promote tasks cannot be part of the action, so with pre promote tasks the action is called once per stage around them.
+func (a *Actions) deployCFActionSteps(task manifest.DeployCF, man manifest.Manifest, deploy Step) Steps {
+	deploy.Uses = compositeAction("deploy-cf")
+	delete(deploy.With, "command")
+	if task.SSORoute != "" {
+		deploy.With["ssoHost"] = strings.TrimSuffix(task.SSORoute, ".public.springernature.app")
+	}
+	if task.Rolling {
+		deploy.With["rolling"] = true
+	}
+
+	if len(task.PrePromote) == 0 {
+		return Steps{deploy}
+	}
+
+	stage := func(name string, stage string) Step {
+		step := deploy
+		step.Name = name
+		step.With = With{"stage": stage}
+		for k, v := range deploy.With {
+			step.With[k] = v
+		}
+		return step
+	}
+
+	steps := Steps{stage("Push", "push")}
+	steps = append(steps, a.prePromoteSteps(task, man)...)
+	steps = append(steps, stage("Promote", "promote"))
+
+	cleanup := stage("Cleanup", "cleanup")
+	cleanup.If = "${{ !cancelled() }}"
+	return append(steps, cleanup)
+}
+
+// dockerPushActionSteps calls the docker-push composite action for each image of the task, first to build and scan
+// all images and then to push them, so that no image is pushed unless all of them pass the scan
+func (a *Actions) dockerPushActionSteps(task manifest.DockerPush, man manifest.Manifest) (steps Steps) {
+	imageTasks := task.ImageTasks()
+	steps = dockerLogin(imageTasks[0].Image, task.Username, task.Password)
+	steps = append(steps, versionParts(imageTasks)...)
+	for _, task := range imageTasks {
+		steps = append(steps, a.dockerPushActionStep(task, man, "Build and scan image", "build"))
+		steps = append(steps, a.saveScanReport(task)...)
+	}
+	for _, task := range imageTasks {
+		push := a.dockerPushActionStep(task, man, "Push image", "push")
+		if task.SignsImage() {
+			push.ID = pushStepID(task)
+		}
+		steps = append(steps, push)
+		steps = append(steps, a.supplyChainSteps(task)...)
+	}
+	return steps
+}
+
+func (a *Actions) dockerPushActionStep(task manifest.DockerPush, man manifest.Manifest, name string, stage string) Step {
+	build := buildImage(a, task, shared.BuildCaches(task))
+
+	push := Step{
+		Name: name,
+		Uses: compositeAction("docker-push"),
+		With: With{
+			"stage":                     stage,
+			"image":                     task.Image,
+			"cache-image":               shared.CachePath(task, ""),
+			"context":                   build.With["context"],
+			"file":                      build.With["file"],
+			"platforms":                 build.With["platforms"],
+			"build-args":                build.With["build-args"],
+			"secrets":                   build.With["secrets"],
+			"tags":                      strings.Join(tags(task, man), "\n"),
+			"trivyignore":               path.Join(a.workingDir, task.Scan.IgnoreFile),
+			"severity":                  strings.Join(task.Scan.Severities(), ","),
+			"repository-dispatch-token": githubSecrets.RepositoryDispatchToken,
+		},
+	}
+	if task.Target != "" {
+		push.With["target"] = task.Target
+	}
+	if task.UseCache {
+		push.With["use-cache"] = true
+	}
+	if task.Scan.IgnoreUnfixed != nil && !*task.Scan.IgnoreUnfixed {
+		push.With["ignore-unfixed"] = false
+	}
+	if task.Scan.Mode == manifest.ScanModeWarn {
+		push.With["ignore-vulnerabilities"] = true
+	}
+	if task.Scan.Report != "" {
+		push.With["report-format"] = task.Scan.Report
+		push.With["report-file"] = path.Join(a.workingDir, task.Scan.ReportFile())
+	}
+	if task.BakeFile != "" {
+		// the build args and secrets are in the env of the step
+		push.With["context"] = build.With["workdir"]
+		push.With["bake-file"] = task.BakeFile
+		push.With["bake-set"] = build.With["set"]
+		push.With["target"] = shared.BakeTarget(task)
+		for _, input := range []string{"file", "build-args", "secrets"} {
+			delete(push.With, input)
+		}
+		push.Env = build.Env
+	}
+
+	return push
+}
@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"code.cloudfoundry.org/cli/util/manifestparser"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// assertActionInputs checks that the steps calling the composite action only pass inputs the action.yml
+// of the action declares, and that they pass all its required inputs
+func assertActionInputs(t *testing.T, action string, steps Steps) {
+	t.Helper()
+
+	content, err := os.ReadFile(path.Join("..", "..", "actions", action, "action.yml"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var actionYml struct {
+		Inputs map[string]struct {
+			Required bool `yaml:"required"`
+		} `yaml:"inputs"`
+	}
+	if !assert.NoError(t, yaml.Unmarshal(content, &actionYml)) {
+		return
+	}
+
+	var inputs []string
+	for input := range actionYml.Inputs {
+		inputs = append(inputs, input)
+	}
+
+	var calls int
+	for _, step := range steps {
+		if step.Uses != compositeAction(action) {
+			continue
+		}
+		calls++
+		for input := range step.With {
+			assert.Contains(t, inputs, input, "step '%s' passes an input %s does not declare", step.Name, action)
+		}
+		for input, declared := range actionYml.Inputs {
+			if declared.Required {
+				assert.Contains(t, step.With, input, "step '%s' does not pass a required input of %s", step.Name, action)
+			}
+		}
+	}
+	assert.NotZero(t, calls, "no step calls %s", action)
+}
+
+func TestCompositeActionInputs(t *testing.T) {
+	a := NewActions("git@github.com:springernature/halfpipe.git", ".halfpipe.io")
+	man := manifest.Manifest{
+		Team:           "halfpipe-team",
+		FeatureToggles: manifest.FeatureToggles{manifest.FeatureCompositeActions},
+		Triggers:       manifest.TriggerList{manifest.GitTrigger{Branch: "main"}},
+	}
+
+	t.Run("deploy-cf", func(t *testing.T) {
+		assertActionInputs(t, "deploy-cf", a.deployCFSteps(manifest.DeployCF{Name: "deploy"}, man))
+
+		task := manifest.DeployCF{
+			Name:          "deploy with everything",
+			SSORoute:      "my-app.public.springernature.app",
+			Rolling:       true,
+			DockerTag:     "version",
+			CfApplication: manifestparser.Application{Docker: &manifestparser.Docker{Image: "eu.gcr.io/halfpipe-io/my-app"}},
+			PrePromote:    manifest.TaskList{manifest.Run{Name: "smoke test", Script: "smoke-test.sh"}},
+		}
+		assertActionInputs(t, "deploy-cf", a.deployCFSteps(task, man))
+	})
+
+	t.Run("docker-push", func(t *testing.T) {
+		assertActionInputs(t, "docker-push", a.dockerPushSteps(manifest.DockerPush{Image: "eu.gcr.io/halfpipe-io/my-image"}, man))
+
+		ignoreUnfixed := false
+		task := manifest.DockerPush{
+			Image:    "eu.gcr.io/halfpipe-io/my-image",
+			Target:   "app",
+			UseCache: true,
+			Sign:     true,
+			Scan:     manifest.Scan{IgnoreUnfixed: &ignoreUnfixed, Mode: manifest.ScanModeWarn, Report: "sarif"},
+		}
+		assertActionInputs(t, "docker-push", a.dockerPushSteps(task, man))
+
+		task = manifest.DockerPush{Image: "eu.gcr.io/halfpipe-io/my-image", BakeFile: "docker-bake.hcl"}
+		assertActionInputs(t, "docker-push", a.dockerPushSteps(task, man))
+	})
+}
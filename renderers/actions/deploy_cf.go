@@ -50,50 +50,126 @@ func (a *Actions) deployCFSteps(task manifest.DeployCF, man manifest.Manifest) (
 	}
 	envVars["CF_ENV_VAR_BUILD_URL"] = "https://github.com/${{github.repository}}/actions/runs/${{github.run_id}}"
 
-	deploySteps := Steps{}
-
-	if task.SSORoute != "" {
-		deploySteps = append(deploySteps, configureSSOStep(task, uses))
+	push := func(name string, command string) Step {
+		push := Step{
+			Name: name,
+			Uses: uses,
+			With: addCommonParams(With{
+				"command": command,
+				"team":    man.Team,
+				"gitUri":  man.Triggers.GetGitTrigger().URI,
+			}),
+			Env: envVars,
+		}
+		if task.CfApplication.Docker != nil {
+			push.With["dockerUsername"] = "_json_key"
+			push.With["dockerPassword"] = "((halfpipe-gcr.private_key_base64))"
+		}
+		if task.DockerTag == "gitref" {
+			push.With["dockerTag"] = "${{ env.GIT_REVISION }}"
+		} else if task.DockerTag == "version" {
+			push.With["dockerTag"] = "${{ env.BUILD_VERSION }}"
+		}
+		return push
 	}
 
-	push := Step{
-		Name: "Push",
+	logsOnFailure := Step{
+		Name: "cf logs --recent",
+		If:   "failure()",
 		Uses: uses,
 		With: addCommonParams(With{
-			"command": "halfpipe-push",
-			"team":    man.Team,
-			"gitUri":  man.Triggers.GetGitTrigger().URI,
+			"command": "halfpipe-logs",
 		}),
-		Env: envVars,
 	}
-	if task.CfApplication.Docker != nil {
-		push.With["dockerUsername"] = "_json_key"
-		push.With["dockerPassword"] = "((halfpipe-gcr.private_key_base64))"
+
+	if man.FeatureToggles.CompositeActions() {
+		return a.deployCFActionSteps(task, man, push("Deploy", ""))
 	}
-	if task.DockerTag == "gitref" {
-		push.With["dockerTag"] = "${{ env.GIT_REVISION }}"
-	} else if task.DockerTag == "version" {
-		push.With["dockerTag"] = "${{ env.BUILD_VERSION }}"
+
+	deploySteps := Steps{}
+
+	if task.SSORoute != "" {
+		deploySteps = append(deploySteps, configureSSOStep(task, uses))
 	}
-	deploySteps = append(deploySteps, push)
 
-	deploySteps = append(deploySteps, Step{
-		Name: "cf logs --recent",
-		If:   "failure()",
-		Uses: uses,
-		With: addCommonParams(With{
-			"command": "halfpipe-logs",
-		}),
-	})
+	if task.Rolling {
+		deploySteps = append(deploySteps, a.rollingDeployCFSteps(task, man, push, logsOnFailure, addCommonParams)...)
+	} else {
+		deploySteps = append(deploySteps, push("Push", "halfpipe-push"), logsOnFailure)
+
+		deploySteps = append(deploySteps, Step{
+			Name: "Check",
+			Uses: uses,
+			With: addCommonParams(With{
+				"command": "halfpipe-check",
+			}),
+		})
+
+		deploySteps = append(deploySteps, a.prePromoteSteps(task, man)...)
+
+		deploySteps = append(deploySteps, Step{
+			Name: "Promote",
+			Uses: uses,
+			With: addCommonParams(With{
+				"command": "halfpipe-promote",
+			}),
+		})
+	}
 
+	sRun := []string{}
+	sRun = append(sRun, `echo ":rocket: **Deployment Successful**" >> $GITHUB_STEP_SUMMARY`)
+	sRun = append(sRun, `echo "" >> $GITHUB_STEP_SUMMARY`)
+	sRun = append(sRun, `echo "[SNPaaS Mission Control](https://mission-control.snpaas.eu/)" >> $GITHUB_STEP_SUMMARY`)
 	deploySteps = append(deploySteps, Step{
-		Name: "Check",
-		Uses: uses,
-		With: addCommonParams(With{
-			"command": "halfpipe-check",
-		}),
+		Name: "Summary",
+		Run:  strings.Join(sRun, "\n"),
 	})
 
+	if !task.Rolling {
+		deploySteps = append(deploySteps, Step{
+			Name: "Cleanup",
+			If:   "${{ !cancelled() }}",
+			Uses: uses,
+			With: addCommonParams(With{
+				"command": "halfpipe-cleanup",
+			}),
+		})
+	}
+
+	steps = append(steps, deploySteps...)
+	return steps
+}
+
+// rollingDeployCFSteps replaces the running app instance by instance instead of promoting a candidate app.
+// Pre promote tasks run against a test app with a single instance, which is deleted after the deploy.
+// The logs are fetched when any of the steps fails, like on Concourse.
+func (a *Actions) rollingDeployCFSteps(task manifest.DeployCF, man manifest.Manifest, push func(name string, command string) Step, logsOnFailure Step, addCommonParams func(With) With) (steps Steps) {
+	if len(task.PrePromote) > 0 {
+		testApp := push("Deploy test app", "halfpipe-push")
+		testApp.With["instances"] = 1
+		steps = append(steps, testApp)
+		steps = append(steps, a.prePromoteSteps(task, man)...)
+	}
+
+	rollingDeploy := push("Rolling deploy", "halfpipe-rolling-deploy")
+	rollingDeploy.With["cli_version"] = "cf7"
+	steps = append(steps, rollingDeploy, logsOnFailure)
+
+	if len(task.PrePromote) > 0 {
+		steps = append(steps, Step{
+			Name: "Remove test app",
+			If:   "${{ !cancelled() }}",
+			Uses: logsOnFailure.Uses,
+			With: addCommonParams(With{
+				"command": "halfpipe-delete-test",
+			}),
+		})
+	}
+	return steps
+}
+
+// prePromoteSteps runs the pre promote tasks against the test route of the candidate app
+func (a *Actions) prePromoteSteps(task manifest.DeployCF, man manifest.Manifest) (steps Steps) {
 	testRoute := shared.BuildTestRoute(task)
 	for _, ppTask := range task.PrePromote {
 		switch ppTask := ppTask.(type) {
@@ -102,13 +178,13 @@ func (a *Actions) deployCFSteps(task manifest.DeployCF, man manifest.Manifest) (
 				ppTask.Vars = make(map[string]string)
 			}
 			ppTask.Vars["TEST_ROUTE"] = testRoute
-			deploySteps = append(deploySteps, a.runSteps(ppTask)...)
+			steps = append(steps, a.runSteps(ppTask)...)
 		case manifest.DockerCompose:
 			if ppTask.Vars == nil {
 				ppTask.Vars = make(map[string]string)
 			}
 			ppTask.Vars["TEST_ROUTE"] = testRoute
-			deploySteps = append(deploySteps, a.dockerComposeSteps(ppTask, man.Team)...)
+			steps = append(steps, a.dockerComposeSteps(ppTask, man.Team)...)
 		case manifest.ConsumerIntegrationTest:
 			if ppTask.Vars == nil {
 				ppTask.Vars = make(map[string]string)
@@ -117,37 +193,9 @@ func (a *Actions) deployCFSteps(task manifest.DeployCF, man manifest.Manifest) (
 			if ppTask.ProviderHost == "" {
 				ppTask.ProviderHost = testRoute
 			}
-			deploySteps = append(deploySteps, a.consumerIntegrationTestSteps(ppTask, man)...)
+			steps = append(steps, a.consumerIntegrationTestSteps(ppTask, man)...)
 		}
 	}
-
-	deploySteps = append(deploySteps, Step{
-		Name: "Promote",
-		Uses: uses,
-		With: addCommonParams(With{
-			"command": "halfpipe-promote",
-		}),
-	})
-
-	sRun := []string{}
-	sRun = append(sRun, `echo ":rocket: **Deployment Successful**" >> $GITHUB_STEP_SUMMARY`)
-	sRun = append(sRun, `echo "" >> $GITHUB_STEP_SUMMARY`)
-	sRun = append(sRun, `echo "[SNPaaS Mission Control](https://mission-control.snpaas.eu/)" >> $GITHUB_STEP_SUMMARY`)
-	deploySteps = append(deploySteps, Step{
-		Name: "Summary",
-		Run:  strings.Join(sRun, "\n"),
-	})
-
-	deploySteps = append(deploySteps, Step{
-		Name: "Cleanup",
-		If:   "${{ !cancelled() }}",
-		Uses: uses,
-		With: addCommonParams(With{
-			"command": "halfpipe-cleanup",
-		}),
-	})
-
-	steps = append(steps, deploySteps...)
 	return steps
 }
 
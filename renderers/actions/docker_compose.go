@@ -35,6 +35,8 @@ func convertDockerComposeToRunTask(task manifest.DockerCompose, team string) man
 		RestoreArtifacts:       task.RestoreArtifacts,
 		SaveArtifactsOnFailure: task.SaveArtifactsOnFailure,
 		Timeout:                task.GetTimeout(),
+		Cache:                  task.Cache,
+		Outputs:                task.Outputs,
 	}
 }
 
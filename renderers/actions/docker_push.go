@@ -5,27 +5,63 @@ import (
 	"path"
 	"strings"
 
+	"github.com/springernature/halfpipe/config"
 	"github.com/springernature/halfpipe/renderers/shared"
 
 	"github.com/springernature/halfpipe/manifest"
 )
 
-func (a *Actions) dockerPushSteps(task manifest.DockerPush) (steps Steps) {
-	steps = dockerLogin(task.Image, task.Username, task.Password)
-	steps = append(steps, buildImage(a, task))
-	steps = append(steps, scanImage(a, task))
-	steps = append(steps, pushImage(task))
-	steps = append(steps, repositoryDispatch(task.Image))
-	steps = append(steps, jobSummary(task.Image, tags(task)))
+func (a *Actions) dockerPushSteps(task manifest.DockerPush, man manifest.Manifest) (steps Steps) {
+	if man.FeatureToggles.CompositeActions() {
+		return a.dockerPushActionSteps(task, man)
+	}
+	// the images are built on the same runner one after the other, so they share the build cache
+	imageTasks := task.ImageTasks()
+	buildCaches := shared.BuildCaches(task)
+	steps = dockerLogin(imageTasks[0].Image, task.Username, task.Password)
+	steps = append(steps, versionParts(imageTasks)...)
+	for _, task := range imageTasks {
+		steps = append(steps, buildImage(a, task, buildCaches))
+		steps = append(steps, scanImage(a, task))
+		steps = append(steps, a.saveScanReport(task)...)
+	}
+	// no image is pushed unless all of them are built and pass the scan
+	for _, task := range imageTasks {
+		steps = append(steps, pushImage(task, man))
+		steps = append(steps, a.supplyChainSteps(task)...)
+		steps = append(steps, repositoryDispatch(task.Image))
+		steps = append(steps, jobSummary(task.Image, tags(task, man)))
+	}
 	return steps
 }
 
-func tags(task manifest.DockerPush) []string {
-	tag1 := fmt.Sprintf("%s:latest", task.Image)
-	tag2 := fmt.Sprintf("%s:${{ env.BUILD_VERSION }}", task.Image)
-	tag3 := fmt.Sprintf("%s:${{ env.GIT_REVISION }}", task.Image)
+func tags(task manifest.DockerPush, man manifest.Manifest) (out []string) {
+	values := map[string]string{
+		"{version}": "${{ env.BUILD_VERSION }}",
+		"{major}":   "${{ env.BUILD_VERSION_MAJOR }}",
+		"{minor}":   "${{ env.BUILD_VERSION_MINOR }}",
+		"{gitref}":  "${{ env.GIT_REVISION }}",
+	}
+	for _, tag := range shared.DockerTags(task, values, man.Triggers.GetGitTrigger().Branch) {
+		out = append(out, fmt.Sprintf("%s:%s", task.Image, tag))
+	}
+	return out
+}
 
-	return []string{tag1, tag2, tag3}
+// versionParts sets the major and minor version used in the tags of the images
+func versionParts(imageTasks []manifest.DockerPush) Steps {
+	for _, task := range imageTasks {
+		if shared.UsesVersionParts(task) {
+			return Steps{{
+				Name: "Set major and minor version",
+				Run: strings.Join([]string{
+					`echo "BUILD_VERSION_MAJOR=$(echo $BUILD_VERSION | cut -d. -f1)" >> $GITHUB_ENV`,
+					`echo "BUILD_VERSION_MINOR=$(echo $BUILD_VERSION | cut -d. -f1-2)" >> $GITHUB_ENV`,
+				}, "\n"),
+			}}
+		}
+	}
+	return nil
 }
 
 func repositoryDispatch(eventName string) Step {
@@ -39,7 +75,7 @@ func repositoryDispatch(eventName string) Step {
 	}
 }
 
-func buildImage(a *Actions, task manifest.DockerPush) Step {
+func buildImage(a *Actions, task manifest.DockerPush, buildCaches []string) Step {
 	buildArgs := map[string]string{
 		"ARTIFACTORY_PASSWORD": "",
 		"ARTIFACTORY_URL":      "",
@@ -52,6 +88,10 @@ func buildImage(a *Actions, task manifest.DockerPush) Step {
 		buildArgs[k] = v
 	}
 
+	if task.BakeFile != "" {
+		return bakeImage(a, task, buildArgs, buildCaches)
+	}
+
 	step := Step{
 		Name: "Build Image",
 		Uses: "docker/build-push-action@v6",
@@ -67,39 +107,87 @@ func buildImage(a *Actions, task manifest.DockerPush) Step {
 		},
 	}
 
+	if task.Target != "" {
+		step.With["target"] = task.Target
+	}
+
 	if task.UseCache {
 		step.With["tags"] = fmt.Sprintf("%s\n%s", step.With["tags"], shared.CachePath(task, "buildcache"))
-		step.With["cache-from"] = fmt.Sprintf("type=registry,ref=%s", shared.CachePath(task, "buildcache"))
+		var cacheFrom []string
+		for _, cache := range buildCaches {
+			cacheFrom = append(cacheFrom, fmt.Sprintf("type=registry,ref=%s", cache))
+		}
+		step.With["cache-from"] = strings.Join(cacheFrom, "\n")
 		step.With["cache-to"] = "type=inline"
 	}
 
 	return step
 }
 
-func scanImage(a *Actions, task manifest.DockerPush) Step {
-	exitCode := 1
-	if task.IgnoreVulnerabilities {
-		exitCode = 0
+// bakeImage builds the target of the image in the bake file. The build args and secrets are passed in the env of the step,
+// as the secrets are only read from the env by bake
+func bakeImage(a *Actions, task manifest.DockerPush, buildArgs map[string]string, buildCaches []string) Step {
+	env := Env{}
+	args := map[string]string{}
+	for k, v := range buildArgs {
+		if v != "" {
+			env[k] = v
+		}
+		args[k] = fmt.Sprintf("${{ env.%s }}", k)
+	}
+	for k, v := range task.Secrets {
+		env[k] = v
+	}
+
+	return Step{
+		Name: "Build Image",
+		Uses: "docker/bake-action@v5",
+		With: With{
+			"workdir":    a.workingDir,
+			"files":      task.BakeFile,
+			"targets":    shared.BakeTarget(task),
+			"push":       true,
+			"provenance": false,
+			"set":        strings.Join(shared.BakeSet(task, shared.CachePath(task, "${{ env.GIT_REVISION }}"), args, buildCaches), "\n"),
+		},
+		Env: env,
 	}
+}
+
+func scanImage(a *Actions, task manifest.DockerPush) Step {
 	prefix := ""
 	if a.workingDir != "" {
 		prefix = fmt.Sprintf("cd %s; ", a.workingDir)
 	}
 
+	commands := shared.TrivyScan(task.Scan, shared.CachePath(task, ":${{ env.GIT_REVISION }}"), "30m")
+
 	step := Step{
 		Name: "Run Trivy vulnerability scanner",
 		Uses: "docker://aquasec/trivy",
 		With: With{
 			"entrypoint": "/bin/sh",
-			"args":       fmt.Sprintf(`-c "%s [ -f .trivyignore ] && echo \"Ignoring the following CVE's due to .trivyignore\" || true; [ -f .trivyignore ] && cat .trivyignore; echo || true; trivy image --timeout 30m --ignore-unfixed --severity CRITICAL --scanners vuln --exit-code %s %s"`, prefix, fmt.Sprint(exitCode), shared.CachePath(task, ":${{ env.GIT_REVISION }}")),
+			"args":       fmt.Sprintf(`-c "%s %s"`, prefix, strings.ReplaceAll(strings.Join(commands, "; "), `"`, `\"`)),
 		},
 	}
 	return step
 }
 
-func pushImage(task manifest.DockerPush) Step {
+// saveScanReport saves the report of the scan as an artifact, also when the scan fails
+func (a *Actions) saveScanReport(task manifest.DockerPush) Steps {
+	if task.Scan.Report == "" {
+		return nil
+	}
+	steps := a.saveArtifacts([]string{task.Scan.ReportFile()})
+	for i := range steps {
+		steps[i].If = "${{ !cancelled() }}"
+	}
+	return steps
+}
+
+func pushImage(task manifest.DockerPush, man manifest.Manifest) Step {
 	var sRun []string
-	for _, tag := range tags(task) {
+	for _, tag := range tags(task, man) {
 		sRun = append(sRun, fmt.Sprintf("docker buildx imagetools create %s --tag %s", shared.CachePath(task, ":${{ env.GIT_REVISION }}"), tag))
 	}
 
@@ -108,19 +196,25 @@ func pushImage(task manifest.DockerPush) Step {
 		Run:  strings.Join(sRun, "\n"),
 	}
 
+	if task.SignsImage() {
+		step.ID = pushStepID(task)
+		step.Run += fmt.Sprintf("\necho \"digest=$(%s)\" >> $GITHUB_OUTPUT", shared.DigestCommand(tags(task, man)[0]))
+	}
+
 	return step
 }
 
+// pushStepID is the id of the step that pushes the image, it outputs the digest of the image to sign
+func pushStepID(task manifest.DockerPush) string {
+	return "push-" + idFromName(task.Image)
+}
+
 func jobSummary(img string, tags []string) Step {
 	var sRun []string
 	sRun = append(sRun, `echo ":ship: **Image Pushed Successfully**" >> $GITHUB_STEP_SUMMARY`)
 	sRun = append(sRun, `echo "" >> $GITHUB_STEP_SUMMARY`)
 
-	// Taken from dockerLogin(task.Image, task.Username, task.Password)
-	// set registry if not docker hub by counting slashes
-	// docker hub format: repository:tag or user/repository:tag
-	// other registries:  another.registry/user/repository:tag
-	if strings.Count(img, "/") > 1 {
+	if config.DockerRegistryHost(img) != "" {
 		registry := fmt.Sprintf("https://%s", img)
 		sRun = append(sRun, fmt.Sprintf(`echo "[%s](%s)" >> $GITHUB_STEP_SUMMARY`, img, registry))
 	} else {
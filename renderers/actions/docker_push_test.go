@@ -36,3 +36,22 @@ func Test_tagWithCachePathDockerHubRegistry(t *testing.T) {
 
 	assert.Equal(t, "eu.gcr.io/halfpipe-io/cache/halfpipe/user:${{ env.GIT_REVISION }}", actual)
 }
+
+func Test_dockerLoginRegistry(t *testing.T) {
+	registry := func(image string) any {
+		return dockerLogin(image, "user", "((password))")[0].With["registry"]
+	}
+
+	assert.Equal(t, "123456789012.dkr.ecr.eu-west-1.amazonaws.com", registry("123456789012.dkr.ecr.eu-west-1.amazonaws.com/app"))
+	assert.Equal(t, "ghcr.io", registry("ghcr.io/app"))
+	assert.Equal(t, "ghcr.io", registry("ghcr.io/org/app"))
+	assert.Equal(t, "localhost:5000", registry("localhost:5000/app"))
+	assert.Nil(t, registry("org/app"))
+	assert.Nil(t, registry("org/team/app"))
+}
+
+func Test_jobSummaryLink(t *testing.T) {
+	assert.Contains(t, jobSummary("123456789012.dkr.ecr.eu-west-1.amazonaws.com/app", nil).Run, "(https://123456789012.dkr.ecr.eu-west-1.amazonaws.com/app)")
+	assert.Contains(t, jobSummary("ghcr.io/org/app", nil).Run, "(https://ghcr.io/org/app)")
+	assert.Contains(t, jobSummary("org/app", nil).Run, "(https://hub.docker.com/r/org/app)")
+}
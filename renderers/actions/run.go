@@ -2,17 +2,20 @@ package actions
 
 import (
 	"fmt"
-	"github.com/springernature/halfpipe/manifest"
+	"sort"
 	"strings"
+
+	"github.com/springernature/halfpipe/manifest"
 )
 
 func (a *Actions) runSteps(task manifest.Run) (steps Steps) {
 	run := Step{
 		Name: task.GetName(),
-		Env:  Env(task.Vars),
+		Env:  outputReferencesToNeeds(task.Vars),
 	}
 
-	if task.Docker.Image != "" {
+	// with a container the whole job runs in it, so the script is run directly
+	if task.Docker.Image != "" && task.Container.Image == "" {
 		prefix := ""
 		if a.workingDir != "" {
 			prefix = fmt.Sprintf("cd %s; ", a.workingDir)
@@ -31,8 +34,15 @@ func (a *Actions) runSteps(task manifest.Run) (steps Steps) {
 	}
 
 	steps = append(steps, dockerLogin(task.Docker.Image, task.Docker.Username, task.Docker.Password)...)
+	if len(task.Cache.Paths) > 0 {
+		steps = append(steps, a.cacheStep(task.GetName(), task.Cache))
+	}
 	steps = append(steps, run)
 
+	if len(task.Outputs) > 0 {
+		steps = append(steps, setOutputs(task.Outputs))
+	}
+
 	if task.SavesArtifacts() {
 		steps = append(steps, a.saveArtifacts(task.SaveArtifacts)...)
 	}
@@ -41,3 +51,34 @@ func (a *Actions) runSteps(task manifest.Run) (steps Steps) {
 	}
 	return steps
 }
+
+// setOutputs reads the outputs of the task from their files, they are made available to later jobs in the outputs of the job
+func setOutputs(outputs map[string]string) Step {
+	var keys []string
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf(`echo "%s=$(cat %s)" >> $GITHUB_OUTPUT`, key, outputs[key]))
+	}
+
+	return Step{
+		Name: "Set outputs",
+		ID:   "outputs",
+		Run:  strings.Join(lines, "\n"),
+	}
+}
+
+// outputReferencesToNeeds replaces the references to the outputs of previous tasks with the outputs of their jobs
+func outputReferencesToNeeds(vars manifest.Vars) Env {
+	env := Env{}
+	for key, value := range vars {
+		env[key] = manifest.ReplaceOutputReferences(value, func(ref manifest.OutputReference) string {
+			return fmt.Sprintf("${{ needs.%s.outputs.%s }}", ref.Task, ref.Key)
+		})
+	}
+	return env
+}
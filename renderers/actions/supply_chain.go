@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/renderers/shared"
+)
+
+// supplyChainSteps generate the SBOM of the pushed image, attach it as an attestation and sign the image
+func (a *Actions) supplyChainSteps(task manifest.DockerPush) (steps Steps) {
+	if !task.SignsImage() {
+		return nil
+	}
+	image := shared.SignedImage(task, fmt.Sprintf("${{ steps.%s.outputs.digest }}", pushStepID(task)))
+
+	name := "Sign image"
+	_, sbomFile, _ := shared.SBOM(task)
+	if task.SBOM != "" {
+		syftFormat, _, _ := shared.SBOM(task)
+		steps = append(steps, Step{
+			Name: "Generate SBOM",
+			Uses: "anchore/sbom-action@v0",
+			With: With{
+				"image":           image,
+				"format":          syftFormat,
+				"output-file":     path.Join(a.workingDir, sbomFile),
+				"upload-artifact": false,
+			},
+		})
+
+		name = "Attest SBOM"
+		if task.Sign {
+			name = "Attest SBOM and sign image"
+		}
+	}
+
+	steps = append(steps, Step{
+		Name: "Install cosign",
+		Uses: "sigstore/cosign-installer@v3",
+	})
+
+	return append(steps, Step{
+		Name: name,
+		Run:  strings.Join(shared.CosignCommands(task, image, sbomFile), "\n"),
+		Env:  shared.CosignEnv(task),
+	})
+}
+
+// supplyChainPermissions are the permissions of the job to sign keyless with the OIDC token of the workflow
+func supplyChainPermissions(task manifest.Task) Permissions {
+	if push, ok := task.(manifest.DockerPush); ok && push.SignsImage() && push.SigningKey == "" {
+		return Permissions{"contents": "read", "id-token": "write"}
+	}
+	return nil
+}
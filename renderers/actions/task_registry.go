@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/renderers/shared"
+)
+
+// TaskRenderer renders the steps of the job for a task of the type it is registered for.
+// Checking out the code, restoring artifacts and notifications are added to the job for all types of task.
+type TaskRenderer func(a *Actions, task manifest.Task, man manifest.Manifest) Steps
+
+var taskRenderers = map[string]TaskRenderer{}
+
+// RegisterTaskRenderer sets the renderer for a type of task registered with manifest.RegisterTask
+func RegisterTaskRenderer(taskType string, renderer TaskRenderer) {
+	taskRenderers[taskType] = renderer
+}
+
+// RunSteps renders a run task, for task types that can be expressed as a run task
+func (a *Actions) RunSteps(task manifest.Run) Steps {
+	return a.runSteps(task)
+}
+
+func init() {
+	RegisterTaskRenderer("docker-push", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.dockerPushSteps(task.(manifest.DockerPush), man)
+	})
+	RegisterTaskRenderer("run", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.runSteps(task.(manifest.Run))
+	})
+	RegisterTaskRenderer("docker-compose", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.dockerComposeSteps(task.(manifest.DockerCompose), man.Team)
+	})
+	RegisterTaskRenderer("consumer-integration-test", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.consumerIntegrationTestSteps(task.(manifest.ConsumerIntegrationTest), man)
+	})
+	RegisterTaskRenderer("deploy-ml-modules", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.runSteps(shared.ConvertDeployMLModules(task.(manifest.DeployMLModules), man))
+	})
+	RegisterTaskRenderer("deploy-ml-zip", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.runSteps(shared.ConvertDeployMLZip(task.(manifest.DeployMLZip), man))
+	})
+	RegisterTaskRenderer("deploy-cf", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.deployCFSteps(task.(manifest.DeployCF), man)
+	})
+	RegisterTaskRenderer("deploy-katee", func(a *Actions, task manifest.Task, man manifest.Manifest) Steps {
+		return a.deployKateeSteps(task.(manifest.DeployKatee))
+	})
+}
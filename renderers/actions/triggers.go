@@ -2,6 +2,8 @@ package actions
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/springernature/halfpipe/manifest"
 )
 
@@ -10,10 +12,23 @@ func (a *Actions) triggers(man manifest.Manifest) (on On) {
 		switch trigger := t.(type) {
 		case manifest.GitTrigger:
 			on.Push = a.onPush(trigger, man.PipelineName())
+			if man.Triggers.HasTagTrigger() {
+				on.Push = a.onTags(trigger, man.Triggers.GetTagTrigger())
+			}
 		case manifest.TimerTrigger:
 			on.Schedule = a.onSchedule(trigger)
 		case manifest.DockerTrigger:
-			on.RepositoryDispatch = a.onRepositoryDispatch(trigger.Image)
+			on.RepositoryDispatch.Types = append(on.RepositoryDispatch.Types, a.onRepositoryDispatch(trigger.Image).Types...)
+		case manifest.PipelineTrigger:
+			// a job in a workflow can only be subscribed to with the event it dispatches, whole workflows of the repo with workflow_run
+			if trigger.Job == "" {
+				on.WorkflowRun.Workflows = append(on.WorkflowRun.Workflows, trigger.Pipeline)
+				on.WorkflowRun.Types = []string{"completed"}
+			} else {
+				on.RepositoryDispatch.Types = append(on.RepositoryDispatch.Types, pipelineEventType(trigger.Pipeline, trigger.Job, jobStatuses[trigger.Status]))
+			}
+		case manifest.PullRequestTrigger:
+			on.PullRequest = a.onPullRequest(trigger, man.Triggers.GetGitTrigger(), man.PipelineName())
 		}
 	}
 	return on
@@ -24,34 +39,63 @@ func (a *Actions) onPush(git manifest.GitTrigger, pipelineName string) (push Pus
 		return push
 	}
 	push.Branches = Branches{git.Branch}
+	push.Paths = paths(git.WatchedPaths, git.IgnoredPaths, pipelineName)
+	return push
+}
+
+// onTags triggers on tags instead of commits to the branch, paths are not evaluated for tags by GitHub
+func (a *Actions) onTags(git manifest.GitTrigger, tag manifest.TagTrigger) (push Push) {
+	if git.ManualTrigger {
+		return push
+	}
+	push.Tags = []string{tag.Pattern}
+	if tag.Semver {
+		push.Tags = []string{tag.VersionPrefix() + "[0-9]+.[0-9]+.[0-9]+"}
+	}
+	return push
+}
+
+// onPullRequest watches the paths of the git trigger unless the pull request trigger sets its own
+func (a *Actions) onPullRequest(pullRequest manifest.PullRequestTrigger, git manifest.GitTrigger, pipelineName string) PullRequest {
+	on := PullRequest{
+		Branches: pullRequest.Branches,
+		Paths:    paths(git.WatchedPaths, git.IgnoredPaths, pipelineName),
+		Types:    pullRequest.Types,
+	}
+	if len(pullRequest.Paths) > 0 {
+		on.Paths = paths(pullRequest.Paths, nil, pipelineName)
+	}
+	return on
+}
 
-	for _, p := range git.WatchedPaths {
+func paths(watchedPaths []string, ignoredPaths []string, pipelineName string) (paths Paths) {
+	for _, p := range watchedPaths {
 		path := fmt.Sprintf("%s**", p)
 		var found bool
-		for _, pp := range push.Paths {
+		for _, pp := range paths {
 			if pp == path {
 				found = true
 			}
 		}
 		if !found {
-			push.Paths = append(push.Paths, p+"**")
+			paths = append(paths, p+"**")
 		}
 	}
 
-	if len(push.Paths) > 0 {
-		push.Paths = append(push.Paths, fmt.Sprintf(".github/workflows/%s.yml", pipelineName))
+	if len(paths) > 0 {
+		paths = append(paths, fmt.Sprintf(".github/workflows/%s.yml", pipelineName))
 	}
 
 	// if there are only ignored paths you first have to include all
-	if len(git.WatchedPaths) == 0 && len(git.IgnoredPaths) > 0 {
-		push.Paths = Paths{"**"}
+	if len(watchedPaths) == 0 && len(ignoredPaths) > 0 {
+		paths = Paths{"**"}
 	}
 
-	for _, p := range git.IgnoredPaths {
-		push.Paths = append(push.Paths, "!"+p+"**")
+	for _, p := range ignoredPaths {
+		paths = append(paths, "!"+p+"**")
 	}
 
-	return push
+	return paths
 }
 
 func (a *Actions) onSchedule(timer manifest.TimerTrigger) []Cron {
@@ -63,3 +107,47 @@ func (a *Actions) onRepositoryDispatch(name string) RepositoryDispatch {
 		Types: []string{"docker-push:" + name},
 	}
 }
+
+// jobStatuses maps the status of a pipeline trigger to the status of a job in GitHub Actions
+var jobStatuses = map[string]string{
+	"succeeded": "success",
+	"failed":    "failure",
+}
+
+func pipelineEventType(pipeline string, jobName string, status string) string {
+	return fmt.Sprintf("pipeline:%s/%s:%s", pipeline, idFromName(jobName), status)
+}
+
+// pipelineDispatch dispatches the status of the job to the repository, so that workflows in it with a pipeline trigger
+// on the job can run. A failed dispatch must not fail the job, e.g. GitHub rejects event types of more than 100 characters.
+func pipelineDispatch(man manifest.Manifest, jobName string, repository string) Step {
+	condition := "success() || failure()"
+	if man.Triggers.HasPullRequestTrigger() {
+		condition = fmt.Sprintf("(%s) && github.event_name != 'pull_request'", condition)
+	}
+	return Step{
+		Name: "Dispatch job status to " + repository,
+		If:   condition,
+		Uses: "peter-evans/repository-dispatch@v3",
+		With: With{
+			"token":      githubSecrets.RepositoryDispatchToken,
+			"repository": repository,
+			"event-type": pipelineEventType(man.Pipeline, jobName, "${{ job.status }}"),
+		},
+		ContinueOnError: true,
+	}
+}
+
+// workflowRunCondition only runs the jobs for completed workflows with the status of the pipeline triggers
+func workflowRunCondition(man manifest.Manifest) string {
+	var workflows []string
+	for _, t := range man.Triggers {
+		if trigger, ok := t.(manifest.PipelineTrigger); ok && trigger.Job == "" {
+			workflows = append(workflows, fmt.Sprintf("(github.event.workflow_run.name == '%s' && github.event.workflow_run.conclusion == '%s')", trigger.Pipeline, jobStatuses[trigger.Status]))
+		}
+	}
+	if len(workflows) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("github.event_name != 'workflow_run' || %s", strings.Join(workflows, " || "))
+}
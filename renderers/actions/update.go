@@ -39,12 +39,30 @@ fi
 
 	steps := Steps{update, push}
 
+	if man.Triggers.HasPullRequestTrigger() {
+		// halfpipe cannot push to the branch of a pull request, so the author has to update the workflow
+		push.If = "steps.sync.outputs.synced == 'false' && github.event_name != 'pull_request'"
+		outOfSync := Step{
+			Name: "Fail pull request with outdated workflow",
+			If:   "steps.sync.outputs.synced == 'false' && github.event_name == 'pull_request'",
+			Run: `echo ':x: The workflow is not in sync with the halfpipe manifest' >> $GITHUB_STEP_SUMMARY
+echo >> $GITHUB_STEP_SUMMARY
+echo 'Run ` + "`halfpipe`" + ` and commit the updated workflow to the pull request.' >> $GITHUB_STEP_SUMMARY
+exit 1
+`,
+		}
+		steps = Steps{update, push, outOfSync}
+	}
+
 	if task.TagRepo {
 		tag := man.PipelineName() + "/v$BUILD_VERSION"
 		tagStep := Step{
 			Name: "Tag commit with " + tag,
 			Run:  fmt.Sprintf("git tag -f %s\ngit push origin %s", tag, tag),
 		}
+		if man.Triggers.HasPullRequestTrigger() {
+			tagStep.If = "github.event_name != 'pull_request'"
+		}
 		steps = append(steps, tagStep)
 	}
 
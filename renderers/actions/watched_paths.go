@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/springernature/halfpipe/config"
+	"github.com/springernature/halfpipe/manifest"
+	"golang.org/x/exp/slices"
+)
+
+const changesJobID = "changes"
+
+// changesJob detects which of the watched paths of the tasks changed, the jobs of the tasks only run when theirs did.
+// Only pushes and pull requests are checked, for other events, like manual runs, the outputs are empty and all jobs run.
+func (a *Actions) changesJob(man manifest.Manifest) (job Job, found bool) {
+	var filters []string
+	outputs := Outputs{}
+	for _, task := range man.Tasks.Flatten() {
+		if len(task.GetWatchedPaths()) == 0 {
+			continue
+		}
+		id := idFromName(task.GetName())
+		filter := []string{id + ":"}
+		for _, p := range paths(task.GetWatchedPaths(), nil, man.PipelineName()) {
+			filter = append(filter, fmt.Sprintf("- '%s'", p))
+		}
+		filters = append(filters, strings.Join(filter, "\n"))
+		outputs[id] = fmt.Sprintf("${{ steps.changes.outputs.%s }}", id)
+	}
+
+	if len(filters) == 0 {
+		return job, false
+	}
+
+	detect := Step{
+		Name: "Detect changes",
+		ID:   "changes",
+		If:   "github.event_name == 'push' || github.event_name == 'pull_request'",
+		Uses: "dorny/paths-filter@v3",
+		With: With{
+			// pushes are compared with the previous push to the same ref
+			"base":    "${{ github.ref_name }}",
+			"filters": strings.Join(filters, "\n"),
+		},
+	}
+
+	return Job{
+		Name:           "Detect changes",
+		RunsOn:         RunsOn{config.ActionsRunnerName},
+		TimeoutMinutes: 10,
+		Outputs:        outputs,
+		Steps:          append(checkoutCode(man.Triggers.GetGitTrigger()), detect),
+	}, true
+}
+
+// watchedPathsConditions returns the needs and conditions of a job in a workflow with path filtered tasks.
+// The job of a task with watched paths is skipped when none of them changed, the jobs that need a job that might
+// be skipped must check the status themselves, as by default a job is skipped when any of its needs is skipped.
+func (a *Actions) watchedPathsConditions(task manifest.Task, needs []string, man manifest.Manifest) ([]string, []string) {
+	var conditions []string
+
+	for _, need := range needs {
+		if slices.Contains(a.skippableJobs, need) {
+			conditions = append(conditions, "!failure() && !cancelled()")
+			// the status check also runs the job when update skipped the jobs after it
+			if man.FeatureToggles.UpdatePipeline() && !slices.Contains(needs, "update") {
+				needs = append(slices.Clone(needs), "update")
+			}
+			break
+		}
+	}
+
+	id := idFromName(task.GetName())
+	if len(task.GetWatchedPaths()) > 0 {
+		needs = append(slices.Clone(needs), changesJobID)
+		conditions = append(conditions, fmt.Sprintf("needs.%s.outputs.%s != 'false'", changesJobID, id))
+	}
+
+	if len(conditions) > 0 {
+		a.skippableJobs = append(a.skippableJobs, id)
+	}
+	return needs, conditions
+}
@@ -18,8 +18,10 @@ type Workflow struct {
 
 type On struct {
 	Push               Push               `yaml:"push,omitempty"`
+	PullRequest        PullRequest        `yaml:"pull_request,omitempty"`
 	RepositoryDispatch RepositoryDispatch `yaml:"repository_dispatch,omitempty"`
 	Schedule           []Cron             `yaml:"schedule,omitempty"`
+	WorkflowRun        WorkflowRun        `yaml:"workflow_run,omitempty"`
 	WorkflowDispatch   WorkflowDispatch   `yaml:"workflow_dispatch"`
 }
 
@@ -29,13 +31,25 @@ type RepositoryDispatch struct {
 	Types []string `yaml:"types,omitempty"`
 }
 
+type WorkflowRun struct {
+	Workflows []string `yaml:"workflows,omitempty"`
+	Types     []string `yaml:"types,omitempty"`
+}
+
 type Cron struct {
 	Expression string `yaml:"cron,omitempty"`
 }
 
 type Push struct {
+	Branches Branches `yaml:"branches,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`
+	Paths    Paths    `yaml:"paths,omitempty"`
+}
+
+type PullRequest struct {
 	Branches Branches `yaml:"branches,omitempty"`
 	Paths    Paths    `yaml:"paths,omitempty"`
+	Types    []string `yaml:"types,omitempty"`
 }
 
 type Branches []string
@@ -55,13 +69,24 @@ type Job struct {
 	Name           string            `yaml:"name,omitempty"`
 	Needs          []string          `yaml:"needs,omitempty"`
 	If             string            `yaml:"if,omitempty"`
-	RunsOn         string            `yaml:"runs-on,omitempty"`
+	RunsOn         RunsOn            `yaml:"runs-on,omitempty"`
+	Permissions    Permissions       `yaml:"permissions,omitempty"`
+	Environment    string            `yaml:"environment,omitempty"`
 	Container      Container         `yaml:"container,omitempty"`
 	TimeoutMinutes int               `yaml:"timeout-minutes,omitempty"`
 	Outputs        map[string]string `yaml:"outputs,omitempty"`
 	Steps          Steps             `yaml:"steps,omitempty"`
 }
 
+type RunsOn []string
+
+func (r RunsOn) MarshalYAML() (interface{}, error) {
+	if len(r) == 1 {
+		return r[0], nil
+	}
+	return []string(r), nil
+}
+
 type Container struct {
 	Image       string
 	Credentials Credentials `yaml:"credentials,omitempty"`
@@ -81,6 +106,7 @@ type Step struct {
 	With             With   `yaml:"with,omitempty"`
 	Env              Env    `yaml:"env,omitempty"`
 	WorkingDirectory string `yaml:"working-directory,omitempty"`
+	ContinueOnError  bool   `yaml:"continue-on-error,omitempty"`
 }
 
 type Steps []Step
@@ -111,6 +137,8 @@ type Env map[string]string
 
 type Outputs map[string]string
 
+type Permissions map[string]string
+
 func (e Env) ToString() string {
 	out := []string{}
 	for k, v := range e {
@@ -133,7 +133,7 @@ func (d deployCF) pushCandidateApp() atc.Step {
 			"command":      "halfpipe-push",
 			"testDomain":   d.task.TestDomain,
 			"manifestPath": d.manifestPath,
-			"gitRefPath":   path.Join(gitDir, ".git", "ref"),
+			"gitRefPath":   gitRevisionFile(d.halfpipeManifest.Triggers),
 			"gitUri":       d.halfpipeManifest.Triggers.GetGitTrigger().URI,
 			"cliVersion":   d.task.CliVersion,
 			"team":         d.team,
@@ -146,9 +146,9 @@ func (d deployCF) pushCandidateApp() atc.Step {
 		push.Params["dockerPassword"] = defaults.Concourse.Docker.Password
 		if d.task.DockerTag != "" {
 			if d.task.DockerTag == "version" {
-				push.Params["dockerTag"] = path.Join(versionName, "version")
+				push.Params["dockerTag"] = buildVersionPath(d.halfpipeManifest)
 			} else if d.task.DockerTag == "gitref" {
-				push.Params["dockerTag"] = path.Join(gitDir, ".git", "ref")
+				push.Params["dockerTag"] = gitRevisionFile(d.halfpipeManifest.Triggers)
 			}
 		}
 	} else {
@@ -164,8 +164,8 @@ func (d deployCF) pushCandidateApp() atc.Step {
 	if len(d.task.PreStart) > 0 {
 		push.Params["preStartCommand"] = strings.Join(d.task.PreStart, "; ")
 	}
-	if d.halfpipeManifest.FeatureToggles.UpdatePipeline() {
-		push.Params["buildVersionPath"] = path.Join("version", "version")
+	if d.halfpipeManifest.FeatureToggles.UpdatePipeline() || d.halfpipeManifest.Triggers.HasTagTrigger() {
+		push.Params["buildVersionPath"] = buildVersionPath(d.halfpipeManifest)
 	}
 
 	if d.task.Rolling {
@@ -203,7 +203,7 @@ func (d deployCF) pushApp() atc.Step {
 			"command":      command,
 			"testDomain":   d.task.TestDomain,
 			"manifestPath": d.manifestPath,
-			"gitRefPath":   path.Join(gitDir, ".git", "ref"),
+			"gitRefPath":   gitRevisionFile(d.halfpipeManifest.Triggers),
 			"gitUri":       d.halfpipeManifest.Triggers.GetGitTrigger().URI,
 			"cliVersion":   cliVersion,
 			"team":         d.team,
@@ -216,9 +216,9 @@ func (d deployCF) pushApp() atc.Step {
 		push.Params["dockerPassword"] = defaults.Concourse.Docker.Password
 		if d.task.DockerTag != "" {
 			if d.task.DockerTag == "version" {
-				push.Params["dockerTag"] = path.Join(versionName, "version")
+				push.Params["dockerTag"] = buildVersionPath(d.halfpipeManifest)
 			} else if d.task.DockerTag == "gitref" {
-				push.Params["dockerTag"] = path.Join(gitDir, ".git", "ref")
+				push.Params["dockerTag"] = gitRevisionFile(d.halfpipeManifest.Triggers)
 			}
 		}
 	} else {
@@ -234,8 +234,8 @@ func (d deployCF) pushApp() atc.Step {
 	if len(d.task.PreStart) > 0 {
 		push.Params["preStartCommand"] = strings.Join(d.task.PreStart, "; ")
 	}
-	if d.halfpipeManifest.FeatureToggles.UpdatePipeline() {
-		push.Params["buildVersionPath"] = path.Join("version", "version")
+	if d.halfpipeManifest.FeatureToggles.UpdatePipeline() || d.halfpipeManifest.Triggers.HasTagTrigger() {
+		push.Params["buildVersionPath"] = buildVersionPath(d.halfpipeManifest)
 	}
 
 	return d.logsOnFailure(stepWithAttemptsAndTimeout(&push, d.task.GetAttempts(), d.task.GetTimeout()))
@@ -19,7 +19,7 @@ func convertDockerComposeToRunTask(task manifest.DockerCompose, man manifest.Man
 	return manifest.Run{
 		Retries: task.Retries,
 		Name:    task.GetName(),
-		Script:  dockerComposeScript(task, man.FeatureToggles.UpdatePipeline()),
+		Script:  dockerComposeScript(task, man.FeatureToggles.UpdatePipeline() || man.Triggers.HasTagTrigger()),
 		Docker: manifest.Docker{
 			Image:    config.DockerRegistry + config.DockerComposeImage,
 			Username: "_json_key",
@@ -31,6 +31,7 @@ func convertDockerComposeToRunTask(task manifest.DockerCompose, man manifest.Man
 		RestoreArtifacts:       task.RestoreArtifacts,
 		SaveArtifactsOnFailure: task.SaveArtifactsOnFailure,
 		Timeout:                task.GetTimeout(),
+		Cache:                  task.Cache,
 	}
 }
 
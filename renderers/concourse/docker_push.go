@@ -15,12 +15,24 @@ const tagList_Dir = "tagList"
 
 var tagListFile = path.Join(tagList_Dir, "tagList")
 
+const digestDir = "digest"
+
+var digestFile = path.Join(digestDir, "digest")
+
 func (c Concourse) dockerPushJob(task manifest.DockerPush, basePath string, man manifest.Manifest) atc.JobConfig {
 	var steps []atc.Step
 
 	steps = append(steps, restoreArtifacts(task)...)
-	steps = append(steps, createTagList(task, man.FeatureToggles.UpdatePipeline())...)
-	steps = append(steps, buildAndPush(task, basePath)...)
+	steps = append(steps, createTagList(task, man)...)
+	buildCaches := shared.BuildCaches(task)
+	for _, task := range task.ImageTasks() {
+		steps = append(steps, buildAndScan(task, basePath, buildCaches, man)...)
+	}
+	// no image is pushed unless all of them are built and pass the scan
+	for _, task := range task.ImageTasks() {
+		steps = append(steps, publishImage(task, man))
+		steps = append(steps, supplyChainSteps(task)...)
+	}
 
 	return atc.JobConfig{
 		Name:         task.GetName(),
@@ -62,9 +74,37 @@ func restoreArtifacts(task manifest.DockerPush) []atc.Step {
 	return []atc.Step{}
 }
 
-func createTagList(task manifest.DockerPush, updatePipeline bool) []atc.Step {
-	gitRefFile := path.Join(gitDir, ".git", "ref")
-	versionFile := path.Join(versionName, "version")
+// dockerTagVariables are the shell variables of the create-tag-list task with the values of the placeholders in the tags
+var dockerTagVariables = map[string]string{
+	"{version}": "$VERSION",
+	"{major}":   "$MAJOR",
+	"{minor}":   "$MINOR",
+	"{gitref}":  "$GIT_REF",
+}
+
+func createTagList(task manifest.DockerPush, man manifest.Manifest) []atc.Step {
+	gitRefFile := gitRevisionFile(man.Triggers)
+	versionFile := buildVersionPath(man)
+	format, placeholders := shared.DockerTagFormat(task, man.Triggers.GetGitTrigger().Branch)
+	printfArgs := []string{fmt.Sprintf("%q", format)}
+	for _, placeholder := range placeholders {
+		printfArgs = append(printfArgs, fmt.Sprintf("%q", dockerTagVariables[placeholder]))
+	}
+
+	commands := []string{
+		fmt.Sprintf("GIT_REF=`[ -f %s ] && cat %s || true`", gitRefFile, gitRefFile),
+		fmt.Sprintf("VERSION=`[ -f %s ] && cat %s || true`", versionFile, versionFile),
+	}
+	if shared.UsesVersionParts(task) {
+		commands = append(commands,
+			"MAJOR=`echo $VERSION | cut -d. -f1`",
+			"MINOR=`echo $VERSION | cut -d. -f1-2`",
+		)
+	}
+	commands = append(commands,
+		fmt.Sprintf("printf %s > %s", strings.Join(printfArgs, " "), tagListFile),
+		fmt.Sprintf("%s $(cat %s)", `printf "Image will be tagged with: %s\n"`, tagListFile),
+	)
 
 	createTagList := &atc.TaskStep{
 		Name: "create-tag-list",
@@ -78,12 +118,7 @@ func createTagList(task manifest.DockerPush, updatePipeline bool) []atc.Step {
 			},
 			Run: atc.TaskRunConfig{
 				Path: "/bin/sh",
-				Args: []string{"-c", strings.Join([]string{
-					fmt.Sprintf("GIT_REF=`[ -f %s ] && cat %s || true`", gitRefFile, gitRefFile),
-					fmt.Sprintf("VERSION=`[ -f %s ] && cat %s || true`", versionFile, versionFile),
-					fmt.Sprintf("%s > %s", `printf "%s %s latest" "$GIT_REF" "$VERSION"`, tagListFile),
-					fmt.Sprintf("%s $(cat %s)", `printf "Image will be tagged with: %s\n"`, tagListFile),
-				}, "\n")},
+				Args: []string{"-c", strings.Join(commands, "\n")},
 			},
 			Inputs: []atc.TaskInputConfig{
 				{Name: gitDir},
@@ -93,18 +128,30 @@ func createTagList(task manifest.DockerPush, updatePipeline bool) []atc.Step {
 			},
 		},
 	}
-	if updatePipeline {
+	if man.FeatureToggles.UpdatePipeline() {
 		createTagList.Config.Inputs = append(createTagList.Config.Inputs, atc.TaskInputConfig{Name: versionName})
 	}
 	return append([]atc.Step{}, stepWithAttemptsAndTimeout(createTagList, task.GetAttempts(), task.Timeout))
 }
 
-func trivyTask(task manifest.DockerPush, fullBasePath string, basePath string) atc.StepConfig {
-	imageFile := shared.CachePath(task, "")
-	gitRef := fmt.Sprintf(":$(cat %s)", pathToGitRef(gitDir, basePath))
+// registryCredentials are the username and password of the task to log in to the registry of the image
+func registryCredentials(task manifest.DockerPush) atc.TaskEnv {
+	return atc.TaskEnv{
+		"REGISTRY_USERNAME": task.Username,
+		"REGISTRY_PASSWORD": task.Password,
+	}
+}
+
+func trivyTask(task manifest.DockerPush, fullBasePath string, basePath string, man manifest.Manifest) atc.StepConfig {
+	image := shared.CachePath(task, fmt.Sprintf(":$(cat %s)", pathToGitRevision(gitDir, basePath, man.Triggers)))
 
-	// temporary: always exit 0 until we have communicated the ignoreVulnerabilites opt-in
-	exitCode := 0
+	commands := shared.TrivyScan(task.Scan, image, fmt.Sprintf("%dm", task.ScanTimeout))
+	if task.Scan.Report != "" {
+		// the report is copied before the last command, which fails the scan
+		report := task.Scan.ReportFile()
+		copyReport := fmt.Sprintf("mkdir -p %s && cp %s %s", fullPathToArtifactsDir(gitDir, basePath, artifactsOutDir, report), report, fullPathToArtifactsDir(gitDir, basePath, artifactsOutDir, report))
+		commands = append(commands[:len(commands)-1], copyReport, commands[len(commands)-1])
+	}
 
 	step := &atc.TaskStep{
 		Name: "trivy",
@@ -118,12 +165,8 @@ func trivyTask(task manifest.DockerPush, fullBasePath string, basePath string) a
 			},
 			Run: atc.TaskRunConfig{
 				Path: "/bin/sh",
-				Args: []string{"-c", strings.Join([]string{
-					`[ -f .trivyignore ] && echo "Ignoring the following CVE's due to .trivyignore" || true`,
-					`[ -f .trivyignore ] && cat .trivyignore; echo || true`,
-					fmt.Sprintf(`trivy image --timeout %dm --ignore-unfixed --severity CRITICAL --scanners vuln --exit-code %d %s%s || true`, task.ScanTimeout, exitCode, imageFile, gitRef),
-				}, "\n")},
-				Dir: fullBasePath,
+				Args: []string{"-c", strings.Join(commands, "\n")},
+				Dir:  fullBasePath,
 			},
 			Params: atc.TaskEnv{
 				"DOCKER_CONFIG_JSON": "((halfpipe-gcr.docker_config))",
@@ -138,13 +181,16 @@ func trivyTask(task manifest.DockerPush, fullBasePath string, basePath string) a
 		step.Config.Inputs = append(step.Config.Inputs, atc.TaskInputConfig{Name: dockerBuildTmpDir})
 	}
 
+	if task.SavesArtifacts() {
+		step.Config.Outputs = append(step.Config.Outputs, atc.TaskOutputConfig{Name: artifactsOutDir})
+	}
+
 	return step
 }
 
-func buildAndPush(task manifest.DockerPush, basePath string) []atc.Step {
+// buildAndScan builds the image to the halfpipe registry and scans it there
+func buildAndScan(task manifest.DockerPush, basePath string, buildCaches []string, man manifest.Manifest) []atc.Step {
 	var steps []atc.Step
-	image, tag := shared.SplitTag(task.Image)
-	dockerImageWithCachePath := shared.CachePath(task, "")
 
 	fullBasePath := path.Join(gitDir, basePath)
 	if task.RestoreArtifacts {
@@ -163,7 +209,11 @@ func buildAndPush(task manifest.DockerPush, basePath string) []atc.Step {
 		"--push",
 		"--provenance false",
 		fmt.Sprintf("--platform %s", strings.Join(task.Platforms, ",")),
-		fmt.Sprintf("--tag %s", shared.CachePath(task, "$(cat git/.git/ref)")),
+		fmt.Sprintf("--tag %s", shared.CachePath(task, fmt.Sprintf("$(cat %s)", gitRevisionFile(man.Triggers)))),
+	}
+
+	if task.Target != "" {
+		buildCommand = append(buildCommand, fmt.Sprintf("--target %s", task.Target))
 	}
 
 	buildArgs := []string{}
@@ -185,14 +235,27 @@ func buildAndPush(task manifest.DockerPush, basePath string) []atc.Step {
 
 	if task.UseCache {
 		buildCommand = append(buildCommand, fmt.Sprintf("--tag %s", shared.CachePath(task, "buildcache")))
-		buildCommand = append(buildCommand, fmt.Sprintf("--cache-from type=registry,ref=%s", shared.CachePath(task, "buildcache")))
+		for _, cache := range buildCaches {
+			buildCommand = append(buildCommand, fmt.Sprintf("--cache-from type=registry,ref=%s", cache))
+		}
 		buildCommand = append(buildCommand, "--cache-to type=inline")
 	}
 
 	buildCommand = append(buildCommand, path.Join(fullBasePath, task.BuildPath))
 
+	buildScript := []string{`echo $DOCKER_CONFIG_JSON > ~/.docker/config.json`}
+	if task.BakeFile != "" {
+		buildScript = append(buildScript, fmt.Sprintf("cd %s", fullBasePath))
+		buildCommand = bakeCommand(task, basePath, buildCaches, man)
+	}
+
 	buildCommandStr := strings.Join(buildCommand, ` \
   `)
+	// the values of the build args of bake are in the command, they must not be printed
+	echoBuildCommand := fmt.Sprintf(`echo $ %s`, strings.ReplaceAll(buildCommandStr, "$", `\$`))
+	if task.BakeFile == "" {
+		echoBuildCommand = fmt.Sprintf(`echo $ %s`, buildCommandStr)
+	}
 	buildStep = &atc.TaskStep{
 		Name:       "build",
 		Privileged: true,
@@ -210,10 +273,9 @@ func buildAndPush(task manifest.DockerPush, basePath string) []atc.Step {
 			Params: params,
 			Run: atc.TaskRunConfig{
 				Path: "/bin/sh",
-				Args: []string{"-c", strings.Join([]string{
-					`echo $DOCKER_CONFIG_JSON > ~/.docker/config.json`,
-					fmt.Sprintf(`echo $ %s`, buildCommandStr),
-					buildCommandStr}, "\n"),
+				Args: []string{"-c", strings.Join(append(buildScript,
+					echoBuildCommand,
+					buildCommandStr), "\n"),
 				},
 			},
 			Inputs: []atc.TaskInputConfig{
@@ -228,9 +290,63 @@ func buildAndPush(task manifest.DockerPush, basePath string) []atc.Step {
 	}
 
 	steps = append(steps, stepWithAttemptsAndTimeout(buildStep, task.GetAttempts(), task.GetTimeout()))
-	steps = append(steps, stepWithAttemptsAndTimeout(trivyTask(task, fullBasePath, basePath), task.GetAttempts(), task.GetTimeout()))
+	trivy := stepWithAttemptsAndTimeout(trivyTask(task, fullBasePath, basePath, man), task.GetAttempts(), task.GetTimeout())
+	if task.SavesArtifacts() {
+		// the report is saved even when the scan fails
+		trivy = atc.Step{Config: &atc.EnsureStep{Step: trivy.Config, Hook: saveArtifactsStep()}}
+	}
+	steps = append(steps, trivy)
+
+	return steps
+}
+
+// bakeCommand builds the target of the image in the bake file, it is run in the directory of the manifest as the paths
+// in the bake file are relative to it
+func bakeCommand(task manifest.DockerPush, basePath string, buildCaches []string, man manifest.Manifest) []string {
+	buildArgs := map[string]string{}
+	for k := range task.Vars {
+		buildArgs[k] = "$" + k
+	}
+
+	command := []string{
+		"docker buildx bake",
+		fmt.Sprintf("-f %s", task.BakeFile),
+		"--push",
+		"--provenance false",
+	}
+	for _, set := range shared.BakeSet(task, shared.CachePath(task, fmt.Sprintf("$(cat %s)", pathToGitRevision(gitDir, basePath, man.Triggers))), buildArgs, buildCaches) {
+		command = append(command, fmt.Sprintf(`--set "%s"`, set))
+	}
+	return append(command, shared.BakeTarget(task))
+}
+
+// publishImage copies the image from the halfpipe registry to the registry of the image with all its tags
+func publishImage(task manifest.DockerPush, man manifest.Manifest) atc.Step {
+	image, tag := shared.SplitTag(task.Image)
+	dockerImageWithCachePath := shared.CachePath(task, "")
+	gitRevision := gitRevisionFile(man.Triggers)
 
-	publishCommand := fmt.Sprintf(`for tag in $(cat %s) %s; do docker buildx imagetools create %s:$(cat git/.git/ref) --tag %s:$tag; done`, tagListFile, tag, dockerImageWithCachePath, image)
+	publishCommand := fmt.Sprintf(`for tag in $(cat %s) %s; do docker buildx imagetools create %s:$(cat %s) --tag %s:$tag; done`, tagListFile, tag, dockerImageWithCachePath, gitRevision, image)
+
+	// the image is built to the halfpipe registry, and copied from there to the registry of the image
+	publishParams := atc.TaskEnv{
+		"DOCKER_CONFIG_JSON": "((halfpipe-gcr.docker_config))",
+	}
+	publishCommands := []string{`echo $DOCKER_CONFIG_JSON > ~/.docker/config.json`}
+	if shared.DockerLogin(task) {
+		for k, v := range registryCredentials(task) {
+			publishParams[k] = v
+		}
+		publishCommands = append(publishCommands, strings.TrimSpace(fmt.Sprintf(`echo "$REGISTRY_PASSWORD" | docker login --username "$REGISTRY_USERNAME" --password-stdin %s`, config.DockerRegistryHost(task.Image))))
+	}
+	publishCommands = append(publishCommands, publishCommand)
+	if task.SignsImage() {
+		// the digest of the pushed image is signed, tags are mutable
+		publishCommands = append(publishCommands,
+			strings.TrimSpace(fmt.Sprintf("set -- $(cat %s) %s", tagListFile, tag)),
+			fmt.Sprintf("%s > %s", shared.DigestCommand(image+":$1"), digestFile),
+		)
+	}
 
 	pushStep := &atc.TaskStep{
 		Name:       "publish-final-image",
@@ -246,16 +362,10 @@ func buildAndPush(task manifest.DockerPush, basePath string) []atc.Step {
 					"username":   "_json_key",
 				},
 			},
-			Params: atc.TaskEnv{
-				"DOCKER_CONFIG_JSON": "((halfpipe-gcr.docker_config))",
-			},
+			Params: publishParams,
 			Run: atc.TaskRunConfig{
 				Path: "/bin/sh",
-				Args: []string{"-c", strings.Join([]string{
-					`echo $DOCKER_CONFIG_JSON > ~/.docker/config.json`,
-					publishCommand,
-				}, "\n"),
-				},
+				Args: []string{"-c", strings.Join(publishCommands, "\n")},
 			},
 			Inputs: []atc.TaskInputConfig{
 				{Name: gitDir},
@@ -263,7 +373,8 @@ func buildAndPush(task manifest.DockerPush, basePath string) []atc.Step {
 			},
 		},
 	}
-	steps = append(steps, stepWithAttemptsAndTimeout(pushStep, task.GetAttempts(), task.GetTimeout()))
-
-	return steps
+	if task.SignsImage() {
+		pushStep.Config.Outputs = []atc.TaskOutputConfig{{Name: digestDir}}
+	}
+	return stepWithAttemptsAndTimeout(pushStep, task.GetAttempts(), task.GetTimeout())
 }
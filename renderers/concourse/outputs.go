@@ -0,0 +1,62 @@
+package concourse
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/springernature/halfpipe/manifest"
+	"golang.org/x/exp/slices"
+)
+
+// outputsAsArtifacts saves the files of the outputs of tasks as artifacts and restores the artifacts in the tasks that refer to them
+func outputsAsArtifacts(tasks manifest.TaskList) (updated manifest.TaskList) {
+	for _, task := range tasks {
+		switch t := task.(type) {
+		case manifest.Parallel:
+			t.Tasks = outputsAsArtifacts(t.Tasks)
+			task = t
+		case manifest.Sequence:
+			t.Tasks = outputsAsArtifacts(t.Tasks)
+			task = t
+		case manifest.DeployCF:
+			t.PrePromote = outputsAsArtifacts(t.PrePromote)
+			task = t
+		case manifest.Run:
+			t.SaveArtifacts = append(slices.Clone(t.SaveArtifacts), outputFiles(t.Outputs)...)
+			t.RestoreArtifacts = t.RestoreArtifacts || len(manifest.TaskOutputReferences(t)) > 0
+			task = t
+		case manifest.DockerCompose:
+			t.SaveArtifacts = append(slices.Clone(t.SaveArtifacts), outputFiles(t.Outputs)...)
+			t.RestoreArtifacts = t.RestoreArtifacts || len(manifest.TaskOutputReferences(t)) > 0
+			task = t
+		}
+		updated = append(updated, task)
+	}
+	return updated
+}
+
+func outputFiles(outputs map[string]string) (files []string) {
+	for _, file := range outputs {
+		if !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// exportOutputReferences exports the vars that refer to outputs of previous tasks with the values read from the restored artifacts
+func exportOutputReferences(vars manifest.Vars, tasks manifest.TaskList) (exports []string) {
+	for key, value := range vars {
+		if len(manifest.OutputReferences(value)) == 0 {
+			continue
+		}
+		value = manifest.ReplaceOutputReferences(value, func(ref manifest.OutputReference) string {
+			file, _ := tasks.OutputFile(ref)
+			return fmt.Sprintf("$(cat %s)", file)
+		})
+		exports = append(exports, fmt.Sprintf(`export %s="%s"`, key, value))
+	}
+	sort.Strings(exports)
+	return exports
+}
@@ -2,7 +2,6 @@ package concourse
 
 import (
 	"fmt"
-	"github.com/springernature/halfpipe/renderers/shared"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -11,6 +10,7 @@ import (
 	"github.com/concourse/concourse/atc"
 	"github.com/springernature/halfpipe/config"
 	"github.com/springernature/halfpipe/manifest"
+	"golang.org/x/exp/slices"
 
 	"sigs.k8s.io/yaml"
 )
@@ -122,7 +122,7 @@ func restoreArtifactTask(man manifest.Manifest) atc.Step {
 	return stepWithAttemptsAndTimeout(taskStep, defaultStepAttempts, defaultStepTimeout)
 }
 
-func (c Concourse) initialPlan(man manifest.Manifest, task manifest.Task, previousTaskNames []string) []atc.Step {
+func (c Concourse) initialPlan(man manifest.Manifest, task manifest.Task, previousTaskNames []string, pathFilteredResources []string) []atc.Step {
 	_, isUpdateTask := task.(manifest.Update)
 	versioningEnabled := man.FeatureToggles.UpdatePipeline()
 
@@ -140,6 +140,13 @@ func (c Concourse) initialPlan(man manifest.Manifest, task manifest.Task, previo
 			}
 			getSteps = append(getSteps, atc.Step{Config: getGit})
 
+			if len(task.GetWatchedPaths()) > 0 {
+				getSteps = append(getSteps, pathFilteredGitGet(pathFilteredGitResourceName(task)))
+			}
+			for _, name := range pathFilteredResources {
+				getSteps = append(getSteps, pathFilteredGitGet(name))
+			}
+
 		case manifest.TimerTrigger:
 			if isUpdateTask || !versioningEnabled {
 				getTimer := &atc.GetStep{Name: trigger.GetTriggerName()}
@@ -173,7 +180,7 @@ func (c Concourse) initialPlan(man manifest.Manifest, task manifest.Task, previo
 	}
 
 	parallelSteps := stepWithAttemptsAndTimeout(parallelizeSteps(getSteps).Config, defaultStepAttempts, defaultStepTimeout)
-	parallelGetStep := c.configureTriggerOnGets(c.addPassedJobsToGets(parallelSteps, previousTaskNames), task, man)
+	parallelGetStep := c.configureTriggerOnGets(c.addPassedJobsToGets(parallelSteps, previousTaskNames), task, man, pathFilteredResources)
 
 	steps := []atc.Step{parallelGetStep}
 
@@ -223,7 +230,8 @@ func (c Concourse) resourceConfigs(man manifest.Manifest) (resourceTypes atc.Res
 	for _, trigger := range man.Triggers {
 		switch trigger := trigger.(type) {
 		case manifest.GitTrigger:
-			resourceConfigs = append(resourceConfigs, c.gitResource(trigger))
+			resourceConfigs = append(resourceConfigs, c.gitResource(trigger, man.Triggers))
+			resourceConfigs = append(resourceConfigs, c.pathFilteredGitResources(man)...)
 		case manifest.TimerTrigger:
 			resourceTypes = append(resourceTypes, cronResourceType())
 			resourceConfigs = append(resourceConfigs, c.cronResource(trigger))
@@ -322,41 +330,19 @@ func (c Concourse) onSuccess(task manifest.Task, man manifest.Manifest) *atc.Ste
 	return &onSuccess
 }
 
-func (c Concourse) taskToJobs(task manifest.Task, man manifest.Manifest, previousTaskNames []string) (job atc.JobConfig) {
-	initialPlan := c.initialPlan(man, task, previousTaskNames)
+func (c Concourse) taskToJobs(task manifest.Task, man manifest.Manifest, previousTaskNames []string, pathFilteredResources []string) (job atc.JobConfig) {
+	initialPlan := c.initialPlan(man, task, previousTaskNames, pathFilteredResources)
 	basePath := man.Triggers.GetGitTrigger().BasePath
 
 	switch task := task.(type) {
-	case manifest.Run:
-		job = c.runJob(task, man, false, basePath)
-
-	case manifest.DockerCompose:
-		runTask := convertDockerComposeToRunTask(task, man)
-		job = c.runJob(runTask, man, true, basePath)
-
-	case manifest.DeployCF:
-		job = c.deployCFJob(task, man, basePath)
-
-	case manifest.DeployKatee:
-		job = c.deployKateeJob(task, man, basePath)
-
-	case manifest.DockerPush:
-		job = c.dockerPushJob(task, basePath, man)
-
-	case manifest.ConsumerIntegrationTest:
-		runTask := convertConsumerIntegrationTestToRunTask(task, man)
-		job = c.runJob(runTask, man, true, basePath)
-
-	case manifest.DeployMLZip:
-		runTask := shared.ConvertDeployMLZip(task, man)
-		job = c.runJob(runTask, man, false, basePath)
-
-	case manifest.DeployMLModules:
-		runTask := shared.ConvertDeployMLModules(task, man)
-		job = c.runJob(runTask, man, false, basePath)
-
 	case manifest.Update:
 		job = c.updateJobConfig(task, man.PipelineName(), basePath)
+	default:
+		render, found := taskRenderers[manifest.TaskType(task)]
+		if !found {
+			panic(fmt.Sprintf("no Concourse renderer is registered for the task '%s' of type %T", task.GetName(), task))
+		}
+		job = render(c, task, man, basePath)
 	}
 
 	job.OnFailure = c.onFailure(task, man)
@@ -377,6 +363,8 @@ func (c Concourse) Render(man manifest.Manifest) (string, error) {
 }
 
 func (c Concourse) RenderAtcConfig(man manifest.Manifest) (cfg atc.Config) {
+	man.Tasks = outputsAsArtifacts(man.Tasks)
+
 	resourceTypes, resourceConfigs := c.resourceConfigs(man)
 	cfg.ResourceTypes = append(cfg.ResourceTypes, resourceTypes...)
 	cfg.Resources = append(cfg.Resources, resourceConfigs...)
@@ -386,6 +374,12 @@ func (c Concourse) RenderAtcConfig(man manifest.Manifest) (cfg atc.Config) {
 		passed     []string
 	}
 
+	type job struct {
+		task   manifest.Task
+		passed []string
+	}
+
+	var taskJobs []job
 	var jobs func(manifest.TaskList, *parentTask)
 	jobs = func(tasks manifest.TaskList, parent *parentTask) {
 		for i, task := range tasks {
@@ -401,11 +395,35 @@ func (c Concourse) RenderAtcConfig(man manifest.Manifest) (cfg atc.Config) {
 			case manifest.Sequence:
 				jobs(task.Tasks, &parentTask{isParallel: false, passed: passed})
 			default:
-				cfg.Jobs = append(cfg.Jobs, c.taskToJobs(task, man, passed))
+				taskJobs = append(taskJobs, job{task: task, passed: passed})
 			}
 		}
 	}
 	jobs(man.Tasks, nil)
+
+	// passed constraints only work for resources that are in the passed jobs, so the path filtered git resource
+	// of a task with watched paths is also got by all the jobs before it
+	passedByName := map[string][]string{}
+	pathFilteredResources := map[string][]string{}
+	var addPathFilteredResource func(names []string, resource string)
+	addPathFilteredResource = func(names []string, resource string) {
+		for _, name := range names {
+			if !slices.Contains(pathFilteredResources[name], resource) {
+				pathFilteredResources[name] = append(pathFilteredResources[name], resource)
+				addPathFilteredResource(passedByName[name], resource)
+			}
+		}
+	}
+	for _, j := range taskJobs {
+		passedByName[j.task.GetName()] = j.passed
+		if len(j.task.GetWatchedPaths()) > 0 {
+			addPathFilteredResource(j.passed, pathFilteredGitResourceName(j.task))
+		}
+	}
+
+	for _, j := range taskJobs {
+		cfg.Jobs = append(cfg.Jobs, c.taskToJobs(j.task, man, j.passed, pathFilteredResources[j.task.GetName()]))
+	}
 	return cfg
 }
 
@@ -430,7 +448,7 @@ func (c Concourse) buildLogRetention(task manifest.Task) *atc.BuildLogRetention
 	return &retention
 }
 
-func (c Concourse) configureTriggerOnGets(step atc.Step, task manifest.Task, man manifest.Manifest) atc.Step {
+func (c Concourse) configureTriggerOnGets(step atc.Step, task manifest.Task, man manifest.Manifest, pathFilteredResources []string) atc.Step {
 	if task.IsManualTrigger() {
 		return step
 	}
@@ -440,6 +458,12 @@ func (c Concourse) configureTriggerOnGets(step atc.Step, task manifest.Task, man
 
 	_ = step.Config.Visit(atc.StepRecursor{
 		OnGet: func(step *atc.GetStep) error {
+			if slices.Contains(pathFilteredResources, step.Name) {
+				// only there for the passed constraints of the jobs after this one
+				step.Trigger = false
+				return nil
+			}
+
 			switch task.(type) {
 			case manifest.Update:
 				if step.Name == (manifest.GitTrigger{}.GetTriggerName()) {
@@ -448,10 +472,13 @@ func (c Concourse) configureTriggerOnGets(step atc.Step, task manifest.Task, man
 					step.Trigger = true
 				}
 			default:
+				watchesPaths := len(task.GetWatchedPaths()) > 0
 				if step.Name == versionName {
-					step.Trigger = true
+					step.Trigger = !watchesPaths
+				} else if watchesPaths && step.Name == pathFilteredGitResourceName(task) {
+					step.Trigger = !manualGitTrigger
 				} else if step.Name == (manifest.GitTrigger{}.GetTriggerName()) {
-					step.Trigger = !versioningEnabled && !manualGitTrigger
+					step.Trigger = !watchesPaths && !versioningEnabled && !manualGitTrigger
 				} else {
 					step.Trigger = !versioningEnabled
 				}
@@ -495,6 +522,30 @@ func pathToGitRef(repoName string, basePath string) (gitRefPath string) {
 	return windowsToLinuxPath(p)
 }
 
+// gitRevisionFile is the file with the commit the git resource checked out, from the root of the job. With a tag
+// trigger .git/ref is the name of the tag, the repo is checked out detached at it so .git/HEAD is the commit
+func gitRevisionFile(triggers manifest.TriggerList) string {
+	if triggers.HasTagTrigger() {
+		return path.Join(gitDir, ".git", "HEAD")
+	}
+	return path.Join(gitDir, ".git", "ref")
+}
+
+func pathToGitRevision(repoName string, basePath string, triggers manifest.TriggerList) string {
+	if triggers.HasTagTrigger() {
+		return windowsToLinuxPath(path.Join(relativePathToRepoRoot(repoName, basePath), ".git", "HEAD"))
+	}
+	return pathToGitRef(repoName, basePath)
+}
+
+// buildVersionPath is the file with the BUILD_VERSION from the root of the job, the name of the tag with a tag trigger
+func buildVersionPath(man manifest.Manifest) string {
+	if man.Triggers.HasTagTrigger() {
+		return path.Join(gitDir, ".git", "ref")
+	}
+	return path.Join(versionName, "version")
+}
+
 func windowsToLinuxPath(path string) (unixPath string) {
 	return strings.Replace(path, `\`, "/", -1)
 }
@@ -21,7 +21,7 @@ var shortResourceCheckInterval = atc.CheckEvery{
 	Interval: 10 * time.Minute,
 }
 
-func (c Concourse) gitResource(trigger manifest.GitTrigger) atc.ResourceConfig {
+func (c Concourse) gitResource(trigger manifest.GitTrigger, triggers manifest.TriggerList) atc.ResourceConfig {
 	sources := atc.Source{
 		"uri": trigger.URI,
 	}
@@ -44,6 +44,16 @@ func (c Concourse) gitResource(trigger manifest.GitTrigger) atc.ResourceConfig {
 
 	sources["branch"] = trigger.Branch
 
+	// with a tag trigger the resource only has versions for tagged commits, and .git/ref is the name of the tag
+	if triggers.HasTagTrigger() {
+		tag := triggers.GetTagTrigger()
+		if tag.Semver {
+			sources["tag_regex"] = fmt.Sprintf(`^%s[0-9]+\.[0-9]+\.[0-9]+$`, regexp.QuoteMeta(tag.VersionPrefix()))
+		} else {
+			sources["tag_filter"] = tag.Pattern
+		}
+	}
+
 	cfg := atc.ResourceConfig{
 		Name:   trigger.GetTriggerName(),
 		Type:   "git",
@@ -10,6 +10,10 @@ import (
 	"github.com/springernature/halfpipe/manifest"
 )
 
+// cachePathsDir is where the cached paths of a task are kept, in the first of config.CacheDirs.
+// Concourse does not keep caches nested in an input, so the paths in the repo are links to it
+const cachePathsDir = "/var/halfpipe/cache/paths"
+
 func (c Concourse) runJob(task manifest.Run, man manifest.Manifest, isDockerCompose bool, basePath string) atc.JobConfig {
 	taskInputs := func() []atc.TaskInputConfig {
 		inputs := []atc.TaskInputConfig{{Name: manifest.GitTrigger{}.GetTriggerName()}}
@@ -47,7 +51,10 @@ func (c Concourse) runJob(task manifest.Run, man manifest.Manifest, isDockerComp
 
 	taskEnv := make(atc.TaskEnv)
 	for key, value := range task.Vars {
-		taskEnv[key] = value
+		// vars that refer to outputs are exported by the script
+		if len(manifest.OutputReferences(value)) == 0 {
+			taskEnv[key] = value
+		}
 	}
 
 	var caches []atc.TaskCacheConfig
@@ -78,21 +85,25 @@ func (c Concourse) runJob(task manifest.Run, man manifest.Manifest, isDockerComp
 	jobConfig.PlanSequence = append(jobConfig.PlanSequence, step)
 
 	if len(task.SaveArtifacts) > 0 {
-		artifactPut := &atc.PutStep{
-			Name: artifactsName,
-			Params: atc.Params{
-				"folder":       artifactsOutDir,
-				"version_file": path.Join(gitDir, ".git", "ref"),
-			},
-			NoGet: true,
-		}
-
-		jobConfig.PlanSequence = append(jobConfig.PlanSequence, stepWithAttemptsAndTimeout(artifactPut, defaultStepAttempts, defaultStepTimeout))
+		jobConfig.PlanSequence = append(jobConfig.PlanSequence, saveArtifactsStep())
 	}
 
 	return jobConfig
 }
 
+// saveArtifactsStep puts the artifacts-out dir of the job to the artifacts resource
+func saveArtifactsStep() atc.Step {
+	artifactPut := &atc.PutStep{
+		Name: artifactsName,
+		Params: atc.Params{
+			"folder":       artifactsOutDir,
+			"version_file": path.Join(gitDir, ".git", "ref"),
+		},
+		NoGet: true,
+	}
+	return stepWithAttemptsAndTimeout(artifactPut, defaultStepAttempts, defaultStepTimeout)
+}
+
 var warningMissingBash = `if ! which bash > /dev/null && [ "$SUPPRESS_BASH_WARNING" != "true" ]; then
   echo "WARNING: Bash is not present in the docker image"
   echo "If your script depends on bash you will get a strange error message like:"
@@ -150,16 +161,29 @@ func runScriptArgs(task manifest.Run, man manifest.Manifest, enableWarningMessag
 		out = append(out, fmt.Sprintf("cp -r %s/. %s\n", pathToArtifactsDir(gitDir, basePath, artifactsInDir), relativePathToRepoRoot(gitDir, basePath)))
 	}
 
-	out = append(out,
-		fmt.Sprintf("export GIT_REVISION=`cat %s`", pathToGitRef(gitDir, basePath)),
-	)
+	if len(task.Cache.Paths) > 0 {
+		out = append(out, "# Linking the cached paths to the halfpipe cache")
+		for _, cachePath := range task.Cache.Paths {
+			cacheDir := path.Join(cachePathsDir, path.Clean(path.Join(basePath, cachePath)))
+			out = append(out, fmt.Sprintf("mkdir -p %s $(dirname %s) && rm -rf %s && ln -s %s %s", cacheDir, cachePath, cachePath, cacheDir, cachePath))
+		}
+		out = append(out, "")
+	}
 
-	if man.FeatureToggles.UpdatePipeline() {
+	out = append(out, fmt.Sprintf("export GIT_REVISION=`cat %s`", pathToGitRevision(gitDir, basePath, man.Triggers)))
+	if man.Triggers.HasTagTrigger() {
+		// .git/ref is the name of the tag
+		out = append(out,
+			fmt.Sprintf("export BUILD_VERSION=`cat %s`", pathToGitRef(gitDir, basePath)),
+		)
+	} else if man.FeatureToggles.UpdatePipeline() {
 		out = append(out,
 			fmt.Sprintf("export BUILD_VERSION=`cat %s`", pathToVersionFile(gitDir, basePath)),
 		)
 	}
 
+	out = append(out, exportOutputReferences(task.Vars, man.Tasks)...)
+
 	scriptCall := fmt.Sprintf(`
 %s
 EXIT_STATUS=$?
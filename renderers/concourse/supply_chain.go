@@ -0,0 +1,116 @@
+package concourse
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/springernature/halfpipe/config"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/renderers/shared"
+)
+
+const sbomDir = "sbom"
+
+// supplyChainSteps generate the SBOM of the pushed image with syft, attach it as an attestation and sign the image with cosign
+func supplyChainSteps(task manifest.DockerPush) (steps []atc.Step) {
+	if !task.SignsImage() {
+		return nil
+	}
+	image := shared.SignedImage(task, fmt.Sprintf("$(cat %s)", digestFile))
+	syftFormat, sbomFile, _ := shared.SBOM(task)
+	sbomFile = path.Join(sbomDir, sbomFile)
+
+	if task.SBOM != "" {
+		sbom := &atc.TaskStep{
+			Name: "sbom",
+			Config: &atc.TaskConfig{
+				Platform: "linux",
+				ImageResource: &atc.ImageResource{
+					Type: "registry-image",
+					Source: atc.Source{
+						"repository": "anchore/syft",
+						"tag":        "debug",
+					},
+				},
+				Params: atc.TaskEnv{
+					"DOCKER_CONFIG_JSON": "((halfpipe-gcr.docker_config))",
+				},
+				Run: atc.TaskRunConfig{
+					Path: "/busybox/sh",
+					Args: []string{"-c", strings.Join([]string{
+						"set -e",
+						`mkdir -p ~/.docker && echo $DOCKER_CONFIG_JSON > ~/.docker/config.json`,
+						fmt.Sprintf("/syft %s -o %s=%s", image, syftFormat, sbomFile),
+					}, "\n")},
+				},
+				Inputs: []atc.TaskInputConfig{
+					{Name: gitDir},
+					{Name: digestDir},
+				},
+				Outputs: []atc.TaskOutputConfig{
+					{Name: sbomDir},
+				},
+			},
+		}
+		if shared.DockerLogin(task) {
+			sbom.Config.Params["SYFT_REGISTRY_AUTH_AUTHORITY"] = registryAuthority(task.Image)
+			sbom.Config.Params["SYFT_REGISTRY_AUTH_USERNAME"] = task.Username
+			sbom.Config.Params["SYFT_REGISTRY_AUTH_PASSWORD"] = task.Password
+		}
+		steps = append(steps, stepWithAttemptsAndTimeout(sbom, task.GetAttempts(), task.GetTimeout()))
+	}
+
+	params := atc.TaskEnv{
+		"DOCKER_CONFIG_JSON": "((halfpipe-gcr.docker_config))",
+	}
+	for k, v := range shared.CosignEnv(task) {
+		params[k] = v
+	}
+
+	commands := []string{"set -e", `mkdir -p ~/.docker && echo $DOCKER_CONFIG_JSON > ~/.docker/config.json`}
+	if shared.DockerLogin(task) {
+		for k, v := range registryCredentials(task) {
+			params[k] = v
+		}
+		commands = append(commands, fmt.Sprintf(`cosign login %s --username "$REGISTRY_USERNAME" --password "$REGISTRY_PASSWORD"`, registryAuthority(task.Image)))
+	}
+	commands = append(commands, shared.CosignCommands(task, image, sbomFile)...)
+
+	sign := &atc.TaskStep{
+		Name: "sign",
+		Config: &atc.TaskConfig{
+			Platform: "linux",
+			ImageResource: &atc.ImageResource{
+				Type: "registry-image",
+				Source: atc.Source{
+					"repository": "cgr.dev/chainguard/cosign",
+					"tag":        "latest-dev",
+				},
+			},
+			Params: params,
+			Run: atc.TaskRunConfig{
+				Path: "/bin/sh",
+				Args: []string{"-c", strings.Join(commands, "\n")},
+			},
+			Inputs: []atc.TaskInputConfig{
+				{Name: gitDir},
+				{Name: digestDir},
+			},
+		},
+	}
+	if task.SBOM != "" {
+		sign.Config.Inputs = append(sign.Config.Inputs, atc.TaskInputConfig{Name: sbomDir})
+	}
+
+	return append(steps, stepWithAttemptsAndTimeout(sign, task.GetAttempts(), task.GetTimeout()))
+}
+
+// registryAuthority is the registry of the image to log in to, index.docker.io for images on Docker Hub
+func registryAuthority(image string) string {
+	if registry := config.DockerRegistryHost(image); registry != "" {
+		return registry
+	}
+	return "index.docker.io"
+}
@@ -0,0 +1,50 @@
+package concourse
+
+import (
+	"github.com/concourse/concourse/atc"
+	"github.com/springernature/halfpipe/manifest"
+	"github.com/springernature/halfpipe/renderers/shared"
+)
+
+// TaskRenderer renders the job for a task of the type it is registered for.
+// The initial plan, notifications and build log retention are added to the job for all types of task.
+type TaskRenderer func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig
+
+var taskRenderers = map[string]TaskRenderer{}
+
+// RegisterTaskRenderer sets the renderer for a type of task registered with manifest.RegisterTask
+func RegisterTaskRenderer(taskType string, renderer TaskRenderer) {
+	taskRenderers[taskType] = renderer
+}
+
+// RunJob renders a run task, for task types that can be expressed as a run task
+func (c Concourse) RunJob(task manifest.Run, man manifest.Manifest, basePath string) atc.JobConfig {
+	return c.runJob(task, man, false, basePath)
+}
+
+func init() {
+	RegisterTaskRenderer("run", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.runJob(task.(manifest.Run), man, false, basePath)
+	})
+	RegisterTaskRenderer("docker-compose", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.runJob(convertDockerComposeToRunTask(task.(manifest.DockerCompose), man), man, true, basePath)
+	})
+	RegisterTaskRenderer("deploy-cf", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.deployCFJob(task.(manifest.DeployCF), man, basePath)
+	})
+	RegisterTaskRenderer("deploy-katee", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.deployKateeJob(task.(manifest.DeployKatee), man, basePath)
+	})
+	RegisterTaskRenderer("docker-push", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.dockerPushJob(task.(manifest.DockerPush), basePath, man)
+	})
+	RegisterTaskRenderer("consumer-integration-test", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.runJob(convertConsumerIntegrationTestToRunTask(task.(manifest.ConsumerIntegrationTest), man), man, true, basePath)
+	})
+	RegisterTaskRenderer("deploy-ml-zip", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.runJob(shared.ConvertDeployMLZip(task.(manifest.DeployMLZip), man), man, false, basePath)
+	})
+	RegisterTaskRenderer("deploy-ml-modules", func(c Concourse, task manifest.Task, man manifest.Manifest, basePath string) atc.JobConfig {
+		return c.runJob(shared.ConvertDeployMLModules(task.(manifest.DeployMLModules), man), man, false, basePath)
+	})
+}
@@ -0,0 +1,38 @@
+package concourse
+
+import (
+	"github.com/concourse/concourse/atc"
+	"github.com/springernature/halfpipe/manifest"
+)
+
+// pathFilteredGitResourceName is the name of the git resource that only has versions for changes to the watched paths of the task
+func pathFilteredGitResourceName(task manifest.Task) string {
+	return restrictAllowedCharacterSet(manifest.GitTrigger{}.GetTriggerName() + "-" + task.GetName())
+}
+
+// pathFilteredGitResources are the git resources of the tasks with watched paths. The jobs of the tasks still get the
+// repo from the git resource, passed the previous jobs, and only trigger on the path filtered resource.
+func (c Concourse) pathFilteredGitResources(man manifest.Manifest) (resourceConfigs atc.ResourceConfigs) {
+	for _, task := range man.Tasks.Flatten() {
+		if len(task.GetWatchedPaths()) == 0 {
+			continue
+		}
+		trigger := man.Triggers.GetGitTrigger()
+		trigger.WatchedPaths = task.GetWatchedPaths()
+
+		resourceConfig := c.gitResource(trigger, man.Triggers)
+		resourceConfig.Name = pathFilteredGitResourceName(task)
+		resourceConfigs = append(resourceConfigs, resourceConfig)
+	}
+	return resourceConfigs
+}
+
+// pathFilteredGitGet only fetches the latest commit, the repo itself comes from the git resource
+func pathFilteredGitGet(name string) atc.Step {
+	return atc.Step{Config: &atc.GetStep{
+		Name: name,
+		Params: map[string]interface{}{
+			"depth": 1,
+		},
+	}}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/springernature/halfpipe/config"
 	"github.com/springernature/halfpipe/manifest"
+	"golang.org/x/exp/slices"
+	"regexp"
 	"strings"
 )
 
@@ -21,6 +23,64 @@ func CachePath(task manifest.DockerPush, tag string) string {
 	}
 }
 
+// BuildCaches returns the registry build caches of the images of the task, an image is built with the caches of all the
+// images so that the images built from the same Dockerfile share their layers
+func BuildCaches(task manifest.DockerPush) (caches []string) {
+	for _, imageTask := range task.ImageTasks() {
+		caches = append(caches, CachePath(imageTask, "buildcache"))
+	}
+	return caches
+}
+
+// BakeTarget returns the target of the image in the bake file of the task, bake builds the 'default' target when it is not set
+func BakeTarget(task manifest.DockerPush) string {
+	if task.Target == "" {
+		return "default"
+	}
+	return task.Target
+}
+
+// BakeSet returns the overrides of the bake target that build it like docker buildx build would be called for the image.
+// The values of the build args are passed as they are referred to on the platform, the secrets are read from the env.
+func BakeSet(task manifest.DockerPush, tag string, buildArgs map[string]string, buildCaches []string) (set []string) {
+	target := BakeTarget(task)
+	set = append(set, fmt.Sprintf("%s.tags=%s", target, tag))
+	if task.UseCache {
+		set = append(set, fmt.Sprintf("%s.tags=%s", target, CachePath(task, "buildcache")))
+	}
+	for _, platform := range task.Platforms {
+		set = append(set, fmt.Sprintf("%s.platform=%s", target, platform))
+	}
+
+	var args []string
+	for k, v := range buildArgs {
+		args = append(args, fmt.Sprintf("%s.args.%s=%s", target, k, v))
+	}
+	slices.Sort(args)
+	set = append(set, args...)
+
+	var secrets []string
+	for k := range task.Secrets {
+		secrets = append(secrets, fmt.Sprintf("%s.secrets=id=%s,env=%s", target, k, k))
+	}
+	slices.Sort(secrets)
+	set = append(set, secrets...)
+
+	if task.UseCache {
+		for _, cache := range buildCaches {
+			set = append(set, fmt.Sprintf("%s.cache-from=type=registry,ref=%s", target, cache))
+		}
+		set = append(set, fmt.Sprintf("%s.cache-to=type=inline", target))
+	}
+	return set
+}
+
+// DockerLogin is true when the image is pushed with the username and password of the task, images in the registries
+// of the organisation are pushed with its credentials
+func DockerLogin(task manifest.DockerPush) bool {
+	return task.Username != "" && !config.IsAllowedDockerRegistry(task.Image)
+}
+
 func SplitTag(image string) (string, string) {
 	split := strings.Split(image, ":")
 	if len(split) == 2 {
@@ -28,3 +88,132 @@ func SplitTag(image string) (string, string) {
 	}
 	return split[0], ""
 }
+
+// DockerTags returns the tags of the image with the placeholders replaced. The values of the placeholders other than
+// {branch} are passed by placeholder as the platform refers to them at runtime, the branch is known when rendering and is made a valid tag
+func DockerTags(task manifest.DockerPush, values map[string]string, branch string) []string {
+	oldNew := []string{"{branch}", TagSafe(branch)}
+	for placeholder, value := range values {
+		oldNew = append(oldNew, placeholder, value)
+	}
+	replacer := strings.NewReplacer(oldNew...)
+
+	var out []string
+	for _, tag := range dockerTags(task) {
+		out = append(out, replacer.Replace(tag))
+	}
+	return out
+}
+
+// DockerTagFormat returns the tags of the image separated by spaces as a printf format, with a %s for each placeholder
+// other than {branch} and the placeholders in the order of their %s
+func DockerTagFormat(task manifest.DockerPush, branch string) (format string, placeholders []string) {
+	format = strings.Join(DockerTags(task, nil, branch), " ")
+	format = dockerTagPlaceholder.ReplaceAllStringFunc(format, func(placeholder string) string {
+		placeholders = append(placeholders, placeholder)
+		return "%s"
+	})
+	return format, placeholders
+}
+
+// UsesVersionParts is true when a tag of the image uses {major} or {minor}
+func UsesVersionParts(task manifest.DockerPush) bool {
+	for _, tag := range dockerTags(task) {
+		if strings.Contains(tag, "{major}") || strings.Contains(tag, "{minor}") {
+			return true
+		}
+	}
+	return false
+}
+
+var dockerTagPlaceholder = regexp.MustCompile(`\{[a-z]+}`)
+
+func dockerTags(task manifest.DockerPush) []string {
+	if len(task.Tags) == 0 {
+		return manifest.DefaultDockerTags
+	}
+	return task.Tags
+}
+
+// TagSafe replaces every character that is not allowed in a docker tag with '-'
+func TagSafe(s string) string {
+	return regexp.MustCompile(`[^A-Za-z0-9_.\-]`).ReplaceAllString(s, "-")
+}
+
+// TrivyScan returns the commands that scan the image for vulnerabilities. With a report the vulnerabilities are written
+// to the report file first, then the last command fails when the image has vulnerabilities and the mode of the scan is fail.
+func TrivyScan(scan manifest.Scan, image string, timeout string) []string {
+	flags := []string{fmt.Sprintf("--timeout %s", timeout)}
+	if scan.IgnoreUnfixed != nil && *scan.IgnoreUnfixed {
+		flags = append(flags, "--ignore-unfixed")
+	}
+	flags = append(flags, fmt.Sprintf("--severity %s", strings.Join(scan.Severities(), ",")), "--scanners vuln")
+	if scan.IgnoreFile != ".trivyignore" {
+		flags = append(flags, fmt.Sprintf("--ignorefile %s", scan.IgnoreFile))
+	}
+	trivy := "trivy image " + strings.Join(flags, " ")
+
+	commands := []string{
+		fmt.Sprintf(`[ -f %s ] && echo "Ignoring the following CVE's due to %s" || true`, scan.IgnoreFile, scan.IgnoreFile),
+		fmt.Sprintf(`[ -f %s ] && cat %s; echo || true`, scan.IgnoreFile, scan.IgnoreFile),
+	}
+
+	if scan.Report != "" {
+		commands = append(commands, fmt.Sprintf("%s --format %s --output %s --exit-code 0 %s", trivy, scan.Report, scan.ReportFile(), image))
+	}
+
+	if scan.Mode == manifest.ScanModeWarn {
+		return append(commands, fmt.Sprintf("%s --exit-code 0 %s || true", trivy, image))
+	}
+	return append(commands, fmt.Sprintf("%s --exit-code 1 %s", trivy, image))
+}
+
+// SBOM returns the syft output format, the file and the cosign attestation type of the SBOM of the task
+func SBOM(task manifest.DockerPush) (syftFormat string, file string, attestationType string) {
+	switch task.SBOM {
+	case "cyclonedx":
+		return "cyclonedx-json", "sbom.cyclonedx.json", "cyclonedx"
+	default:
+		return "spdx-json", "sbom.spdx.json", "spdxjson"
+	}
+}
+
+// DigestCommand returns the command that prints the digest of the pushed image, the image is signed by its digest
+// as the tags can be moved to other images
+func DigestCommand(image string) string {
+	return fmt.Sprintf("docker buildx imagetools inspect %s --format '{{.Manifest.Digest}}'", image)
+}
+
+// SignedImage returns the image by the digest the supply chain commands refer to
+func SignedImage(task manifest.DockerPush, digest string) string {
+	image, _ := SplitTag(task.Image)
+	return fmt.Sprintf("%s@%s", image, digest)
+}
+
+// CosignCommands returns the commands that attach the SBOM file as an attestation to the image and sign the image.
+// Without a signing key cosign signs keyless, with the identity of the workflow.
+func CosignCommands(task manifest.DockerPush, image string, sbomFile string) (commands []string) {
+	key := ""
+	if task.SigningKey != "" {
+		key = " --key env://COSIGN_PRIVATE_KEY"
+	}
+
+	if task.SBOM != "" {
+		_, _, attestationType := SBOM(task)
+		commands = append(commands, fmt.Sprintf("cosign attest --yes%s --type %s --predicate %s %s", key, attestationType, sbomFile, image))
+	}
+	if task.Sign {
+		commands = append(commands, fmt.Sprintf("cosign sign --yes%s %s", key, image))
+	}
+	return commands
+}
+
+// CosignEnv returns the environment cosign reads the signing key from
+func CosignEnv(task manifest.DockerPush) map[string]string {
+	env := map[string]string{}
+	if task.SigningKey != "" {
+		env["COSIGN_PRIVATE_KEY"] = task.SigningKey
+		env["COSIGN_PASSWORD"] = task.SigningKeyPassword
+	}
+	return env
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/springernature/halfpipe/project"
 	"io"
 	"os/exec"
+	"path"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -78,7 +79,11 @@ func (p planner) getHalfpipeManifest() (man manifest.Manifest, err error) {
 		return man, err
 	}
 
-	man, errs := manifest.Parse(yamlString)
+	readFile := func(filePath string) (string, error) {
+		return linters.ReadFile(p.fs, path.Join(path.Dir(halfpipeFilePath), filePath))
+	}
+
+	man, _, errs := manifest.ParseWithIncludes(halfpipeFilePath, yamlString, readFile)
 	if len(errs) > 0 {
 		return man, errs[0]
 	}